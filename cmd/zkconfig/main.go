@@ -0,0 +1,220 @@
+// Command zkconfig reads and edits service configuration stored in ZooKeeper.
+//
+// Usage:
+//
+//	zkconfig [flags] get [key]
+//	zkconfig [flags] set <key> <value>
+//	zkconfig [flags] delete <key>
+//	zkconfig [flags] diff <file>
+//	zkconfig [flags] export [file]
+//	zkconfig [flags] import <file>
+//
+// Values passed to set are parsed as JSON when possible and stored as plain
+// strings otherwise. Files used by diff, export and import hold plain JSON.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
+	"go.uber.org/zap"
+)
+
+func main() {
+	hosts := flag.String("hosts", envOrDefault("ZK_CONNECT_STRING", "localhost:2181"), "ZooKeeper connect string, such as zk1:2181,zk2:2181/chroot")
+	service := flag.String("service", os.Getenv("SERVICE_NAME"), "service whose config is edited")
+	common := flag.String("common", "", "edit the shared config with this name instead of the service config")
+	profile := flag.String("profile", "", "edit the profile-specific config, such as prod")
+	chroot := flag.String("chroot", os.Getenv("ZK_CHROOT"), "chroot path prefix")
+	user := flag.String("user", os.Getenv("ZK_DIGEST_USER"), "digest auth user")
+	password := flag.String("password", os.Getenv("ZK_DIGEST_PASSWORD"), "digest auth password")
+	verbose := flag.Bool("v", false, "enable debug logging")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	if *service == "" && *common == "" {
+		fail(fmt.Errorf("either -service or -common is required"))
+	}
+
+	logger := zap.NewNop()
+	if *verbose {
+		logger, _ = zap.NewDevelopment()
+	}
+
+	config := zookeeper.DefaultConfig()
+	config.ServiceName = *service
+	config.CommonLibName = *common
+	config.Chroot = *chroot
+
+	// Connect without loading the config so that malformed nodes can be repaired
+	client, err := zookeeper.NewClientFromConfig(config, logger,
+		zookeeper.WithConnectString(*hosts),
+		zookeeper.WithDigestAuth(*user, *password),
+		zookeeper.WithoutConfigLoad())
+	if err != nil {
+		fail(err)
+	}
+
+	nodePath := config.ServiceConfigPath(*profile)
+	if *common != "" {
+		nodePath = config.CommonConfigPath(*profile)
+	}
+
+	err = run(client, nodePath, flag.Arg(0), flag.Args()[1:])
+	client.Close()
+	if err != nil {
+		fail(err)
+	}
+}
+
+// run dispatches a single command against the config node
+func run(client *zookeeper.Client, nodePath, command string, args []string) error {
+	switch command {
+	case "get":
+		document, err := client.GetConfigDocument(nodePath)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return printJSON(document.Data)
+		}
+		value, exists := zookeeper.LookupConfig(document.Data, args[0])
+		if !exists {
+			return fmt.Errorf("key %s not found in %s", args[0], nodePath)
+		}
+		return printJSON(value)
+
+	case "set":
+		if len(args) != 2 {
+			return fmt.Errorf("set requires a key and a value")
+		}
+		return client.SetConfigKey(nodePath, args[0], parseValue(args[1]))
+
+	case "delete":
+		if len(args) != 1 {
+			return fmt.Errorf("delete requires a key")
+		}
+		return client.DeleteConfigKey(nodePath, args[0])
+
+	case "diff":
+		if len(args) != 1 {
+			return fmt.Errorf("diff requires a file")
+		}
+		local, err := readFile(args[0])
+		if err != nil {
+			return err
+		}
+		document, err := client.GetConfigDocument(nodePath)
+		if err != nil {
+			return err
+		}
+		for _, change := range zookeeper.DiffConfig(document.Data, local) {
+			switch change.Type {
+			case zookeeper.ChangeAdded:
+				fmt.Printf("+ %s: %v\n", change.Key, change.NewValue)
+			case zookeeper.ChangeRemoved:
+				fmt.Printf("- %s: %v\n", change.Key, change.OldValue)
+			case zookeeper.ChangeModified:
+				fmt.Printf("~ %s: %v -> %v\n", change.Key, change.OldValue, change.NewValue)
+			}
+		}
+		return nil
+
+	case "export":
+		document, err := client.GetConfigDocument(nodePath)
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			return printJSON(document.Data)
+		}
+		data, err := json.MarshalIndent(document.Data, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return os.WriteFile(args[0], append(data, '\n'), 0o644)
+
+	case "import":
+		if len(args) != 1 {
+			return fmt.Errorf("import requires a file")
+		}
+		local, err := readFile(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = client.ReplaceConfigDocument(nodePath, local, zookeeper.AnyVersion)
+		return err
+
+	default:
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// parseValue interprets a command line value as JSON, falling back to a string
+func parseValue(raw string) any {
+	var value any
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return raw
+	}
+	return value
+}
+
+// readFile loads a plain JSON config document
+func readFile(name string) (map[string]any, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return config, nil
+}
+
+// printJSON writes a value to stdout as indented JSON
+func printJSON(value any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// envOrDefault returns an environment variable or a fallback when unset
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// fail prints an error and exits
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "zkconfig:", err)
+	os.Exit(1)
+}
+
+// usage prints the command help
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: zkconfig [flags] <command> [args]
+
+Commands:
+  get [key]            print the config document or a single key
+  set <key> <value>    set a key; dotted keys address nested objects
+  delete <key>         remove a key
+  diff <file>          show differences between ZooKeeper and a local JSON file
+  export [file]        write the config document as JSON to a file or stdout
+  import <file>        replace the config document with a local JSON file
+
+Flags:
+`)
+	flag.PrintDefaults()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/db"
 	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/logger"
@@ -25,8 +26,14 @@ type Application struct {
 	Environment   string
 	RedisManager  *redis.Manager
 	ZKClient      *zookeeper.Client
+	Registration  *zookeeper.Registration
+
+	shutdownHooks []func(context.Context) error
 }
 
+// ShutdownTimeout bounds how long shutdown hooks may run during Cleanup
+const ShutdownTimeout = 30 * time.Second
+
 // NewApplication initializes and returns a new Application instance
 func NewApplication() (*Application, error) {
 	// Load environment variables
@@ -57,6 +64,9 @@ func NewApplication() (*Application, error) {
 		return nil, fmt.Errorf("failed to initialize ZooKeeper client: %w", err)
 	}
 
+	// Allow gRPC clients to dial zk:///<service> targets
+	zookeeper.RegisterGRPCResolver(zkClient)
+
 	// Initialize database
 	database, err := db.InitDB(zkClient)
 	if err != nil {
@@ -85,8 +95,40 @@ func NewApplication() (*Application, error) {
 	}, nil
 }
 
-// Cleanup performs cleanup of application resources
+// RegisterService registers this instance in ZooKeeper for service discovery
+func (app *Application) RegisterService(instance zookeeper.ServiceInstance) error {
+	registration, err := app.ZKClient.Register(instance)
+	if err != nil {
+		return fmt.Errorf("failed to register service: %w", err)
+	}
+	app.Registration = registration
+	return nil
+}
+
+// OnShutdown registers a hook that Cleanup runs before closing shared
+// resources, such as a stream consumer's Shutdown. Hooks run in reverse
+// registration order.
+func (app *Application) OnShutdown(hook func(context.Context) error) {
+	app.shutdownHooks = append(app.shutdownHooks, hook)
+}
+
+// Cleanup performs cleanup of application resources. The service is
+// deregistered first so that clients stop routing to it while it drains.
 func (app *Application) Cleanup() {
+	if app.Registration != nil {
+		if err := app.Registration.Deregister(); err != nil {
+			log.Printf("failed to deregister service: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	for i := len(app.shutdownHooks) - 1; i >= 0; i-- {
+		if err := app.shutdownHooks[i](ctx); err != nil {
+			log.Printf("shutdown hook failed: %v", err)
+		}
+	}
+
 	if err := app.Logger.Sync(); err != nil {
 		log.Printf("failed to sync logger: %v", err)
 	}
@@ -98,12 +140,9 @@ func (app *Application) Cleanup() {
 	}
 
 	if app.DB != nil {
-		sqlDB, err := app.DB.DB()
-		if err != nil {
+		if sqlDB, err := app.DB.DB(); err != nil {
 			log.Printf("failed to get database instance: %v", err)
-			return
-		}
-		if err := sqlDB.Close(); err != nil {
+		} else if err := sqlDB.Close(); err != nil {
 			log.Printf("failed to close database connection: %v", err)
 		}
 	}
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	pb "github.com/Kunal726/market-mosaic-common-lib-go/proto"
+	"github.com/gin-gonic/gin"
+)
+
+// Principal is the authenticated user of a request
+type Principal struct {
+	UserID      int64
+	Authorities []string
+}
+
+// PrincipalFromContext returns the user set by the auth middleware, which
+// stores either a gRPC token response or an HTTP validation response
+func PrincipalFromContext(c *gin.Context) (Principal, bool) {
+	user, exists := c.Get(UserContextKey)
+	if !exists {
+		return Principal{}, false
+	}
+
+	switch u := user.(type) {
+	case *pb.TokenResponse:
+		return Principal{UserID: u.GetUserId(), Authorities: u.GetAuthorities()}, true
+	case *TokenValidationResponse:
+		return Principal{UserID: int64(u.UserID), Authorities: u.Authorities}, true
+	default:
+		return Principal{}, false
+	}
+}
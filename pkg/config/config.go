@@ -2,8 +2,10 @@ package config
 
 import (
 	"sync"
+	"time"
 
 	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
+	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
@@ -18,7 +20,8 @@ type ConfigManager struct {
 	logger   *zap.Logger
 }
 
-// NewConfigManager creates a new ConfigManager instance
+// NewConfigManager creates the process-wide ConfigManager from environment
+// variables. Later calls return the same instance.
 func NewConfigManager(logger *zap.Logger) (*ConfigManager, error) {
 	var err error
 	once.Do(func() {
@@ -35,11 +38,35 @@ func NewConfigManager(logger *zap.Logger) (*ConfigManager, error) {
 	return instance, err
 }
 
+// NewConfigManagerWithClient creates a ConfigManager around an existing
+// ZooKeeper client. Unlike NewConfigManager it is not a singleton.
+func NewConfigManagerWithClient(zkClient *zookeeper.Client, logger *zap.Logger) *ConfigManager {
+	return &ConfigManager{
+		zkClient: zkClient,
+		logger:   logger,
+	}
+}
+
+// NewConfigManagerFromConfig connects a new ZooKeeper client from an explicit
+// configuration and wraps it in a ConfigManager that is not a singleton
+func NewConfigManagerFromConfig(config *zookeeper.Config, logger *zap.Logger, opts ...zookeeper.ClientOption) (*ConfigManager, error) {
+	zkClient, err := zookeeper.NewClientFromConfig(config, logger, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewConfigManagerWithClient(zkClient, logger), nil
+}
+
 // GetInstance returns the singleton instance of ConfigManager
 func GetInstance() *ConfigManager {
 	return instance
 }
 
+// Client returns the underlying ZooKeeper client
+func (cm *ConfigManager) Client() *zookeeper.Client {
+	return cm.zkClient
+}
+
 // GetStringValueByKey retrieves a string value from service configuration
 func (cm *ConfigManager) GetStringValueByKey(key string, isCommon bool) (string, error) {
 	return cm.zkClient.GetStringValueByKey(key, isCommon)
@@ -50,6 +77,41 @@ func (cm *ConfigManager) GetConfigValueByKey(key string, isCommon bool) (interfa
 	return cm.zkClient.GetConfigValueByKey(key, isCommon)
 }
 
+// GetInt retrieves an integer value, coercing numeric strings
+func (cm *ConfigManager) GetInt(key string, isCommon bool) (int, error) {
+	return cm.zkClient.GetInt(key, isCommon)
+}
+
+// GetBool retrieves a boolean value, coercing strings such as "true" or "1"
+func (cm *ConfigManager) GetBool(key string, isCommon bool) (bool, error) {
+	return cm.zkClient.GetBool(key, isCommon)
+}
+
+// GetDuration retrieves a duration value, coercing strings such as "5s"
+func (cm *ConfigManager) GetDuration(key string, isCommon bool) (time.Duration, error) {
+	return cm.zkClient.GetDuration(key, isCommon)
+}
+
+// GetStringSlice retrieves a list of strings, coercing comma separated strings
+func (cm *ConfigManager) GetStringSlice(key string, isCommon bool) ([]string, error) {
+	return cm.zkClient.GetStringSlice(key, isCommon)
+}
+
+// GetOrDefault retrieves a configuration value or returns defaultValue if the key is missing
+func (cm *ConfigManager) GetOrDefault(key string, isCommon bool, defaultValue any) any {
+	return cm.zkClient.GetOrDefault(key, isCommon, defaultValue)
+}
+
+// VersionHandler serves the current config versions and change history
+func (cm *ConfigManager) VersionHandler() gin.HandlerFunc {
+	return cm.zkClient.VersionHandler()
+}
+
+// VersionField returns a log field describing the config snapshots in use
+func (cm *ConfigManager) VersionField() zap.Field {
+	return cm.zkClient.VersionField()
+}
+
 // RefreshData manually triggers a refresh of the configurations
 func (cm *ConfigManager) RefreshData() {
 	cm.logger.Info("Manual configuration refresh triggered")
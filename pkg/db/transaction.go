@@ -0,0 +1,70 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"gorm.io/gorm"
+)
+
+// txContextKey is the context key holding the ambient transaction of one
+// database, so that transactions on different databases do not mix
+type txContextKey struct {
+	owner any
+}
+
+// TxManager runs units of work in database transactions carried by the context
+type TxManager struct {
+	db *gorm.DB
+}
+
+// NewTxManager creates a new transaction manager
+func NewTxManager(db *gorm.DB) *TxManager {
+	return &TxManager{
+		db: db,
+	}
+}
+
+// WithTransaction runs fn in a transaction that is committed if fn returns
+// nil and rolled back if it returns an error or panics. Only calls that take
+// the context passed to fn, such as the repository's Context methods or
+// queries on DB(ctx), take part in the transaction; calls without it run and
+// commit on their own connection. When ctx already
+// carries a transaction on the same database, fn runs in a savepoint of it
+// instead, so a failing nested unit of work only undoes its own changes. The
+// options are ignored for nested transactions.
+func (m *TxManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...*sql.TxOptions) error {
+	return Conn(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txContextKey{owner: owner(m.db)}, tx))
+	}, opts...)
+}
+
+// DB returns the connection to use for ctx: its ambient transaction or the
+// manager's database
+func (m *TxManager) DB(ctx context.Context) *gorm.DB {
+	return Conn(ctx, m.db)
+}
+
+// Conn returns the ambient transaction ctx carries for db, or db itself when
+// there is none, bound to ctx
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := TxFromContext(ctx, db); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// TxFromContext returns the ambient transaction ctx carries for db
+func TxFromContext(ctx context.Context, db *gorm.DB) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txContextKey{owner: owner(db)}).(*gorm.DB)
+	return tx, ok
+}
+
+// owner identifies the database behind a connection. Sessions and
+// transactions derived from the same gorm.Open share their sql.DB.
+func owner(db *gorm.DB) any {
+	if sqlDB, err := db.DB(); err == nil {
+		return sqlDB
+	}
+	return db.Config
+}
@@ -0,0 +1,76 @@
+package featureflags
+
+import (
+	"hash/fnv"
+	"slices"
+	"strconv"
+)
+
+const (
+	// ConfigKey is the ZooKeeper config key holding the flags document
+	ConfigKey = "FEATURE_FLAGS"
+
+	// ContextKey is the gin context key holding the evaluated flags
+	ContextKey = "featureFlags"
+)
+
+// Flag represents a single feature flag definition
+type Flag struct {
+	Enabled     bool     `json:"enabled"`
+	KillSwitch  bool     `json:"killSwitch"`
+	Rollout     *float64 `json:"rollout,omitempty"`
+	Authorities []string `json:"authorities,omitempty"`
+	Users       []int64  `json:"users,omitempty"`
+}
+
+// Principal holds the request attributes used to evaluate flags
+type Principal struct {
+	UserID      int64
+	Authorities []string
+}
+
+// Evaluate reports whether the flag is on for the given principal, which may be nil.
+// A kill switch always wins, explicitly listed users are always on, authority
+// targeting restricts the audience and the rollout percentage is applied last.
+func (f Flag) Evaluate(name string, principal *Principal) bool {
+	if f.KillSwitch || !f.Enabled {
+		return false
+	}
+
+	if principal != nil && slices.Contains(f.Users, principal.UserID) {
+		return true
+	}
+
+	if len(f.Authorities) > 0 {
+		if principal == nil || !hasAnyAuthority(principal.Authorities, f.Authorities) {
+			return false
+		}
+	}
+
+	if f.Rollout == nil || *f.Rollout >= 100 {
+		return true
+	}
+	if principal == nil || *f.Rollout <= 0 {
+		return false
+	}
+	return bucket(name, principal.UserID) < *f.Rollout
+}
+
+// hasAnyAuthority checks if any of the granted authorities is in the targeted set
+func hasAnyAuthority(granted, targeted []string) bool {
+	for _, authority := range granted {
+		if slices.Contains(targeted, authority) {
+			return true
+		}
+	}
+	return false
+}
+
+// bucket hashes the flag name and user id into a stable value in [0, 100)
+func bucket(name string, userID int64) float64 {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{':'})
+	h.Write([]byte(strconv.FormatInt(userID, 10)))
+	return float64(h.Sum32()%10000) / 100
+}
@@ -0,0 +1,74 @@
+package featureflags
+
+import (
+	"fmt"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
+	"go.uber.org/zap"
+)
+
+// Manager reads flag definitions from ZooKeeper config and evaluates them
+type Manager struct {
+	flags  *zookeeper.ConfigValue[map[string]Flag]
+	logger *zap.Logger
+}
+
+// NewManager creates a new feature flag manager
+func NewManager(zkClient *zookeeper.Client, logger *zap.Logger) *Manager {
+	return &Manager{
+		flags:  zookeeper.NewConfigValue[map[string]Flag](zkClient, ConfigKey, false),
+		logger: logger,
+	}
+}
+
+// Flags returns the current flag definitions. Service config takes precedence
+// over common config; a missing document means no flags are defined. The
+// document is parsed once per config version and the returned map must not
+// be modified.
+func (m *Manager) Flags() (map[string]Flag, error) {
+	flags, exists, err := m.flags.Get()
+	if err != nil {
+		return nil, fmt.Errorf("invalid feature flags: %w", err)
+	}
+	if !exists || flags == nil {
+		return map[string]Flag{}, nil
+	}
+	return flags, nil
+}
+
+// IsEnabled evaluates a single flag for the given principal, which may be nil
+func (m *Manager) IsEnabled(name string, principal *Principal) bool {
+	flags, err := m.Flags()
+	if err != nil {
+		m.logger.Warn("Failed to load feature flags", zap.Error(err))
+		return false
+	}
+
+	flag, exists := flags[name]
+	if !exists {
+		return false
+	}
+	return flag.Evaluate(name, principal)
+}
+
+// EvaluateAll evaluates every defined flag for the given principal
+func (m *Manager) EvaluateAll(principal *Principal) (Evaluated, error) {
+	flags, err := m.Flags()
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated := make(Evaluated, len(flags))
+	for name, flag := range flags {
+		evaluated[name] = flag.Evaluate(name, principal)
+	}
+	return evaluated, nil
+}
+
+// Evaluated holds the flag results for a single request
+type Evaluated map[string]bool
+
+// IsEnabled reports whether the named flag is on; unknown flags are off
+func (e Evaluated) IsEnabled(name string) bool {
+	return e[name]
+}
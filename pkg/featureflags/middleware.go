@@ -0,0 +1,51 @@
+package featureflags
+
+import (
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/auth"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Middleware evaluates all flags for the authenticated principal and stores
+// the result in the gin context. It must run after the auth middleware.
+func (m *Manager) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		evaluated, err := m.EvaluateAll(principalFromContext(c))
+		if err != nil {
+			m.logger.Warn("Failed to evaluate feature flags", zap.Error(err))
+			evaluated = Evaluated{}
+		}
+
+		c.Set(ContextKey, evaluated)
+		c.Next()
+	}
+}
+
+// GetFlagsFromContext retrieves the evaluated flags from the context
+func GetFlagsFromContext(c *gin.Context) (Evaluated, bool) {
+	value, exists := c.Get(ContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	evaluated, ok := value.(Evaluated)
+	return evaluated, ok
+}
+
+// IsEnabled reports whether the named flag is on for the current request
+func IsEnabled(c *gin.Context, name string) bool {
+	evaluated, ok := GetFlagsFromContext(c)
+	if !ok {
+		return false
+	}
+	return evaluated.IsEnabled(name)
+}
+
+// principalFromContext builds a Principal from the user set by the auth middleware
+func principalFromContext(c *gin.Context) *Principal {
+	user, ok := auth.PrincipalFromContext(c)
+	if !ok {
+		return nil
+	}
+	return &Principal{UserID: user.UserID, Authorities: user.Authorities}
+}
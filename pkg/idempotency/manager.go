@@ -0,0 +1,152 @@
+package idempotency
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderKey is the request header carrying the client's idempotency key
+	HeaderKey = "Idempotency-Key"
+
+	// HeaderReplayed is set on responses replayed from a previous request
+	HeaderReplayed = "Idempotent-Replayed"
+
+	// DefaultTTL is how long a response is kept for replay
+	DefaultTTL = 24 * time.Hour
+
+	// DefaultLockTimeout is the lease of the lock held while a request is processed
+	DefaultLockTimeout = 30 * time.Second
+
+	// DefaultWait is how long a duplicate waits for the original request to finish
+	DefaultWait = 5 * time.Second
+
+	// DefaultMaxBodySize is the largest request body read to fingerprint a request
+	DefaultMaxBodySize = 1 << 20
+
+	// DefaultMaxResponseSize is the largest response body stored for replay
+	DefaultMaxResponseSize = 1 << 20
+
+	// MaxKeyLength is the longest idempotency key accepted
+	MaxKeyLength = 255
+
+	// keyNamespace prefixes idempotency keys in Redis
+	keyNamespace = "idempotency"
+
+	// pollInterval is how often a waiting duplicate checks for the stored response
+	pollInterval = 50 * time.Millisecond
+)
+
+// record is a completed request stored for replay
+type record struct {
+	Fingerprint string              `json:"fingerprint"`
+	Status      int                 `json:"status"`
+	Header      map[string][]string `json:"header,omitempty"`
+	Body        []byte              `json:"body,omitempty"`
+}
+
+// Manager stores and replays responses of requests carrying an idempotency key
+type Manager struct {
+	store    *redis.Store[record]
+	locker   *redis.Locker
+	logger   *zap.Logger
+	methods  []string
+	required bool
+	ttl      time.Duration
+	lockTTL  time.Duration
+	wait     time.Duration
+	maxBody  int64
+	maxStore int
+}
+
+// Option represents a function that configures the Manager
+type Option func(*Manager)
+
+// WithMethods sets the HTTP methods the key is honoured on
+func WithMethods(methods ...string) Option {
+	return func(m *Manager) {
+		m.methods = methods
+	}
+}
+
+// WithRequired rejects authenticated requests that do not carry a key
+func WithRequired(required bool) Option {
+	return func(m *Manager) {
+		m.required = required
+	}
+}
+
+// WithTTL sets how long a response is kept for replay
+func WithTTL(ttl time.Duration) Option {
+	return func(m *Manager) {
+		m.ttl = ttl
+	}
+}
+
+// WithLock sets the lease of the in-flight lock and how long a duplicate
+// waits for the original request before being rejected
+func WithLock(timeout, wait time.Duration) Option {
+	return func(m *Manager) {
+		m.lockTTL = timeout
+		m.wait = wait
+	}
+}
+
+// WithMaxBodySize sets the largest request body accepted with a key. Larger
+// requests are rejected with 413.
+func WithMaxBodySize(size int64) Option {
+	return func(m *Manager) {
+		m.maxBody = size
+	}
+}
+
+// WithMaxResponseSize sets the largest response body stored for replay.
+// Larger responses are passed through but not stored, so retries are
+// processed again.
+func WithMaxResponseSize(size int) Option {
+	return func(m *Manager) {
+		m.maxStore = size
+	}
+}
+
+// NewManager creates a new idempotency manager
+func NewManager(redisManager *redis.Manager, logger *zap.Logger, opts ...Option) *Manager {
+	manager := &Manager{
+		store:    redis.NewStore[record](redisManager, keyNamespace),
+		locker:   redis.NewLocker(redisManager, redis.WithFencing(false)),
+		logger:   logger,
+		methods:  []string{http.MethodPost, http.MethodPatch},
+		ttl:      DefaultTTL,
+		lockTTL:  DefaultLockTimeout,
+		wait:     DefaultWait,
+		maxBody:  DefaultMaxBodySize,
+		maxStore: DefaultMaxResponseSize,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(manager)
+	}
+
+	return manager
+}
+
+// storable reports whether a response is kept for replay. Server errors and
+// transient client errors, such as conflicts and rate limiting, are not, so
+// that retries are processed again.
+func storable(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout, http.StatusConflict, http.StatusTooEarly, http.StatusTooManyRequests:
+		return false
+	}
+	return status < http.StatusInternalServerError
+}
+
+// applies reports whether requests with the method honour the key
+func (m *Manager) applies(method string) bool {
+	return slices.Contains(m.methods, method)
+}
@@ -0,0 +1,272 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/auth"
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/dtos"
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Middleware makes requests carrying an Idempotency-Key safe to retry. The
+// first request is processed and its response stored; retries with the same
+// key and payload get the stored response, retries with a different payload
+// get 409, and duplicates arriving while the first is still running wait for
+// it or get 409. Keys are scoped to the authenticated user, so it should run
+// after the auth middleware; unauthenticated requests have no owner to scope
+// keys to and are processed without idempotency. Server errors, transient
+// client errors and responses over the maximum size are not stored so they can
+// be retried, and requests are let through unprotected when Redis is
+// unavailable.
+func (m *Manager) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owner, ok := scope(c)
+		if !m.applies(c.Request.Method) || !ok {
+			c.Next()
+			return
+		}
+
+		key := c.GetHeader(HeaderKey)
+		if key == "" {
+			if m.required {
+				abort(c, http.StatusBadRequest, HeaderKey+" header is required")
+				return
+			}
+			c.Next()
+			return
+		}
+		if len(key) > MaxKeyLength {
+			abort(c, http.StatusBadRequest, HeaderKey+" header is too long")
+			return
+		}
+
+		fingerprint, err := fingerprint(c, m.maxBody)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			abort(c, http.StatusRequestEntityTooLarge, "request body is too large")
+			return
+		}
+		if err != nil {
+			abort(c, http.StatusBadRequest, "failed to read request body")
+			return
+		}
+
+		id := owner + ":" + key
+		ctx := context.WithoutCancel(c.Request.Context())
+
+		stored, err := m.lookup(ctx, id)
+		if err != nil {
+			m.logger.Warn("Idempotency lookup failed, processing request", zap.Error(err))
+			c.Next()
+			return
+		}
+		if stored != nil {
+			m.replay(c, stored, fingerprint)
+			return
+		}
+
+		lock, err := m.locker.TryObtain(ctx, m.store.Key(id)+":lock", m.lockTTL)
+		if errors.Is(err, redis.ErrLockNotObtained) {
+			if stored, err := m.waitFor(c.Request.Context(), id); err == nil && stored != nil {
+				m.replay(c, stored, fingerprint)
+				return
+			}
+			abort(c, http.StatusConflict, "a request with this "+HeaderKey+" is already in progress")
+			return
+		}
+		if err != nil {
+			m.logger.Warn("Failed to lock idempotency key, processing request", zap.Error(err))
+			c.Next()
+			return
+		}
+		defer func() {
+			if err := lock.Release(ctx); err != nil {
+				m.logger.Warn("Failed to release idempotency lock", zap.Error(err))
+			}
+		}()
+
+		// The original request may have completed between the lookup and the lock
+		if stored, err := m.lookup(ctx, id); err == nil && stored != nil {
+			m.replay(c, stored, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, limit: m.maxStore}
+		c.Writer = recorder
+		c.Next()
+
+		status := recorder.Status()
+		if !storable(status) {
+			return
+		}
+		if recorder.overflow {
+			m.logger.Warn("Response too large to store for idempotent replay",
+				zap.String("path", c.FullPath()), zap.Int("limit", m.maxStore))
+			return
+		}
+
+		completed := record{
+			Fingerprint: fingerprint,
+			Status:      status,
+			Header:      storedHeader(recorder.Header()),
+			Body:        recorder.body.Bytes(),
+		}
+		if err := m.store.Set(ctx, id, completed, m.ttl); err != nil {
+			m.logger.Error("Failed to store idempotent response", zap.Error(err))
+		}
+	}
+}
+
+// lookup returns the stored response for id, or nil if there is none
+func (m *Manager) lookup(ctx context.Context, id string) (*record, error) {
+	stored, err := m.store.Get(ctx, id)
+	if errors.Is(err, redis.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+// waitFor polls for the response of an in-flight request
+func (m *Manager) waitFor(ctx context.Context, id string) (*record, error) {
+	deadline := time.NewTimer(m.wait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			stored, err := m.lookup(ctx, id)
+			if err != nil || stored != nil {
+				return stored, err
+			}
+		case <-deadline.C:
+			return nil, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// replay writes a stored response, or 409 if the key was used for a different request
+func (m *Manager) replay(c *gin.Context, stored *record, fingerprint string) {
+	if stored.Fingerprint != fingerprint {
+		abort(c, http.StatusConflict, HeaderKey+" was already used for a different request")
+		return
+	}
+
+	header := c.Writer.Header()
+	for name, values := range stored.Header {
+		header[name] = values
+	}
+	header.Set(HeaderReplayed, "true")
+	header.Set("Content-Length", strconv.Itoa(len(stored.Body)))
+	c.Writer.WriteHeader(stored.Status)
+	if _, err := c.Writer.Write(stored.Body); err != nil {
+		m.logger.Warn("Failed to write replayed response", zap.Error(err))
+	}
+	c.Abort()
+}
+
+// fingerprint hashes the method, path, query and body of a request and
+// restores the body for the handler. Bodies over maxBody are not read.
+func fingerprint(c *gin.Context, maxBody int64) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		if err != nil {
+			return "", err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// scope returns the owner of a key so that users cannot replay each other's
+// responses, or false for unauthenticated requests
+func scope(c *gin.Context) (string, bool) {
+	user, ok := auth.PrincipalFromContext(c)
+	if !ok {
+		return "", false
+	}
+	return "user-" + strconv.FormatInt(user.UserID, 10), true
+}
+
+// storedHeader copies the response headers worth replaying
+func storedHeader(header http.Header) map[string][]string {
+	stored := make(map[string][]string, len(header))
+	for name, values := range header {
+		switch name {
+		case "Content-Length", "Date", "Set-Cookie":
+			continue
+		}
+		stored[name] = values
+	}
+	return stored
+}
+
+// abort ends the request with an error response
+func abort(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, dtos.BaseResponseDTO{
+		Status:  false,
+		Code:    status,
+		Message: message,
+	})
+}
+
+// responseRecorder captures the response body while writing it through. It
+// stops capturing once the body grows past limit.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	limit    int
+	overflow bool
+}
+
+// Write implements io.Writer
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	if r.capture(len(data)) {
+		r.body.Write(data)
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// WriteString implements io.StringWriter
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	if r.capture(len(s)) {
+		r.body.WriteString(s)
+	}
+	return r.ResponseWriter.WriteString(s)
+}
+
+// capture reports whether n more bytes fit in the recorded body, dropping
+// what was recorded once they do not
+func (r *responseRecorder) capture(n int) bool {
+	if r.overflow {
+		return false
+	}
+	if r.body.Len()+n > r.limit {
+		r.overflow = true
+		r.body = bytes.Buffer{}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultKeyPrefix is the default prefix of rate limit keys in Redis
+	DefaultKeyPrefix = "ratelimit"
+
+	// DefaultFallbackPeriod is how long the in-memory store is used after a Redis failure
+	DefaultFallbackPeriod = 5 * time.Second
+)
+
+// Limiter enforces rate limits read from ZooKeeper config using Redis
+type Limiter struct {
+	manager        *redis.Manager
+	rules          *zookeeper.ConfigValue[Rules]
+	logger         *zap.Logger
+	keyPrefix      string
+	fallbackPeriod time.Duration
+	fallbackUntil  atomic.Int64
+	memory         *memoryStore
+}
+
+// Option represents a function that configures the Limiter
+type Option func(*Limiter)
+
+// WithKeyPrefix sets the prefix of rate limit keys in Redis
+func WithKeyPrefix(prefix string) Option {
+	return func(l *Limiter) {
+		l.keyPrefix = prefix
+	}
+}
+
+// WithFallbackPeriod sets how long the in-memory store is used after a Redis failure
+func WithFallbackPeriod(period time.Duration) Option {
+	return func(l *Limiter) {
+		l.fallbackPeriod = period
+	}
+}
+
+// NewLimiter creates a new rate limiter
+func NewLimiter(manager *redis.Manager, zkClient *zookeeper.Client, logger *zap.Logger, opts ...Option) *Limiter {
+	limiter := &Limiter{
+		manager:        manager,
+		rules:          zookeeper.NewConfigValue[Rules](zkClient, ConfigKey, false),
+		logger:         logger,
+		keyPrefix:      DefaultKeyPrefix,
+		fallbackPeriod: DefaultFallbackPeriod,
+		memory:         newMemoryStore(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(limiter)
+	}
+
+	return limiter
+}
+
+// Rules returns the current rate limit rules. Service config takes precedence
+// over common config; a missing document means no limits are enforced. The
+// document is parsed once per config version and the result must not be modified.
+func (l *Limiter) Rules() (Rules, error) {
+	rules, _, err := l.rules.Get()
+	if err != nil {
+		return Rules{}, fmt.Errorf("invalid rate limits: %w", err)
+	}
+	return rules, nil
+}
+
+// Allow counts a request against the rule for the given client key. While
+// Redis is unavailable requests are counted in memory instead.
+func (l *Limiter) Allow(ctx context.Context, key string, rule Rule) (Result, error) {
+	if err := rule.validate(); err != nil {
+		return Result{}, err
+	}
+
+	now := time.Now()
+	redisKey := l.keyPrefix + ":" + key
+	if now.UnixMilli() >= l.fallbackUntil.Load() {
+		result, err := allowRedis(ctx, l.manager.Client(), redisKey, rule, now)
+		if err == nil {
+			return result, nil
+		}
+		l.logger.Warn("Redis rate limiting failed, using in-memory fallback",
+			zap.String("key", key), zap.Duration("period", l.fallbackPeriod), zap.Error(err))
+		l.fallbackUntil.Store(now.Add(l.fallbackPeriod).UnixMilli())
+	}
+	return l.memory.allow(redisKey, rule, now), nil
+}
@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// ConfigKey is the ZooKeeper config key holding the rate limit rules
+	ConfigKey = "RATE_LIMITS"
+
+	// APIKeyHeader is the request header identifying API key clients
+	APIKeyHeader = "X-API-Key"
+
+	// defaultRuleName names the bucket shared by routes without their own rule
+	defaultRuleName = "default"
+)
+
+// Algorithm identifies how requests are counted
+type Algorithm string
+
+// Supported algorithms
+const (
+	// AlgorithmSlidingWindow allows Limit requests in any Window long period
+	AlgorithmSlidingWindow Algorithm = "sliding-window"
+
+	// AlgorithmTokenBucket refills Limit tokens per Window up to Burst tokens
+	AlgorithmTokenBucket Algorithm = "token-bucket"
+)
+
+// KeyBy identifies which client attribute a limit is counted against
+type KeyBy string
+
+// Supported client keys. Requests without a user or API key fall back to the client IP.
+const (
+	KeyByIP     KeyBy = "ip"
+	KeyByUser   KeyBy = "user"
+	KeyByAPIKey KeyBy = "api-key"
+)
+
+// Rule represents a single rate limit definition
+type Rule struct {
+	Algorithm Algorithm `json:"algorithm"`
+	Limit     int64     `json:"limit"`
+	Window    Duration  `json:"window"`
+	Burst     int64     `json:"burst,omitempty"`
+	KeyBy     KeyBy     `json:"keyBy,omitempty"`
+	Disabled  bool      `json:"disabled,omitempty"`
+}
+
+// Rules holds the rate limit configuration. Routes are keyed by "METHOD /path"
+// or "/path" using gin route patterns such as "GET /api/v1/quotes/:symbol".
+// The default rule applies to all other routes and is shared between them.
+type Rules struct {
+	Default *Rule           `json:"default,omitempty"`
+	Routes  map[string]Rule `json:"routes,omitempty"`
+}
+
+// ForRoute returns the rule for a route and the name its counters are kept under
+func (r Rules) ForRoute(method, route string) (Rule, string, bool) {
+	if rule, exists := r.Routes[method+" "+route]; exists {
+		return rule, method + " " + route, true
+	}
+	if rule, exists := r.Routes[route]; exists {
+		return rule, route, true
+	}
+	if r.Default != nil {
+		return *r.Default, defaultRuleName, true
+	}
+	return Rule{}, "", false
+}
+
+// validate checks that a rule can be enforced
+func (r Rule) validate() error {
+	switch r.Algorithm {
+	case "", AlgorithmSlidingWindow, AlgorithmTokenBucket:
+	default:
+		return fmt.Errorf("unsupported rate limit algorithm %q", r.Algorithm)
+	}
+	switch r.KeyBy {
+	case "", KeyByIP, KeyByUser, KeyByAPIKey:
+	default:
+		return fmt.Errorf("unsupported rate limit key %q", r.KeyBy)
+	}
+	if r.Limit <= 0 {
+		return fmt.Errorf("rate limit must be positive")
+	}
+	// Windows are enforced in milliseconds, so shorter ones would round to zero
+	if time.Duration(r.Window) < time.Millisecond {
+		return fmt.Errorf("rate limit window must be at least 1ms")
+	}
+	return nil
+}
+
+// capacity returns the size of the token bucket
+func (r Rule) capacity() int64 {
+	if r.Burst > 0 {
+		return r.Burst
+	}
+	return r.Limit
+}
+
+// Duration is a time.Duration that unmarshals from strings such as "1m" or
+// from a number of seconds
+type Duration time.Duration
+
+// UnmarshalJSON implements json.Unmarshaler
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", v, err)
+		}
+		*d = Duration(parsed)
+	case float64:
+		*d = Duration(v * float64(time.Second))
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
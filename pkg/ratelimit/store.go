@@ -0,0 +1,254 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// memorySweepInterval is how often idle in-memory counters are dropped
+const memorySweepInterval = time.Minute
+
+var (
+	// slidingWindowScript keeps a sorted set of request timestamps. It returns
+	// {allowed, remaining, reset ms, retry after ms}.
+	slidingWindowScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", KEYS[1], window)
+local reset = window
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+local retry = 0
+if allowed == 0 then
+	retry = reset
+end
+return {allowed, limit - count, reset, retry}
+`)
+
+	// tokenBucketScript keeps the token count and last refill time in a hash.
+	// It returns {allowed, remaining, reset ms, retry after ms}.
+	tokenBucketScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local capacity = tonumber(ARGV[3])
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1]) or capacity
+local ts = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call("PEXPIRE", KEYS[1], math.max(reset, 1))
+local retry = 0
+if allowed == 0 then
+	retry = math.ceil((1 - tokens) / rate)
+end
+return {allowed, math.floor(tokens), reset, retry}
+`)
+)
+
+// Result describes the outcome of a rate limit check
+type Result struct {
+	Allowed    bool
+	Limit      int64
+	Remaining  int64
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+// allowRedis evaluates a rule against the counters stored in Redis
+func allowRedis(ctx context.Context, client goredis.Scripter, key string, rule Rule, now time.Time) (Result, error) {
+	var values []int64
+	var err error
+	switch rule.Algorithm {
+	case AlgorithmTokenBucket:
+		values, err = tokenBucketScript.Run(ctx, client, []string{key},
+			now.UnixMilli(), strconv.FormatFloat(refillRate(rule), 'g', -1, 64), rule.capacity()).Int64Slice()
+	default:
+		member, tokenErr := requestID(now)
+		if tokenErr != nil {
+			return Result{}, tokenErr
+		}
+		values, err = slidingWindowScript.Run(ctx, client, []string{key},
+			now.UnixMilli(), time.Duration(rule.Window).Milliseconds(), rule.Limit, member).Int64Slice()
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to evaluate rate limit %s: %w", key, err)
+	}
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("unexpected rate limit reply for %s: %v", key, values)
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limitOf(rule),
+		Remaining:  values[1],
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
+
+// refillRate returns the token bucket refill rate in tokens per millisecond
+func refillRate(rule Rule) float64 {
+	return float64(rule.Limit) / float64(time.Duration(rule.Window).Milliseconds())
+}
+
+// limitOf returns the limit advertised to clients
+func limitOf(rule Rule) int64 {
+	if rule.Algorithm == AlgorithmTokenBucket {
+		return rule.capacity()
+	}
+	return rule.Limit
+}
+
+// requestID returns a unique sorted set member for a request
+func requestID(now time.Time) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate request id: %w", err)
+	}
+	return strconv.FormatInt(now.UnixMilli(), 10) + "-" + hex.EncodeToString(buf), nil
+}
+
+// memoryStore evaluates rules in process memory. It is used while Redis is
+// unavailable, so limits are then enforced per instance.
+type memoryStore struct {
+	mu        sync.Mutex
+	windows   map[string][]int64
+	buckets   map[string]*bucketState
+	expiry    map[string]int64
+	lastSweep int64
+}
+
+// bucketState is the in-memory token bucket
+type bucketState struct {
+	tokens float64
+	ts     int64
+}
+
+// newMemoryStore creates an empty in-memory store
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		windows: make(map[string][]int64),
+		buckets: make(map[string]*bucketState),
+		expiry:  make(map[string]int64),
+	}
+}
+
+// allow evaluates a rule against the in-memory counters
+func (s *memoryStore) allow(key string, rule Rule, now time.Time) Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nowMs := now.UnixMilli()
+	s.sweep(nowMs)
+
+	if rule.Algorithm == AlgorithmTokenBucket {
+		return s.allowTokenBucket(key, rule, nowMs)
+	}
+	return s.allowSlidingWindow(key, rule, nowMs)
+}
+
+// allowSlidingWindow mirrors slidingWindowScript
+func (s *memoryStore) allowSlidingWindow(key string, rule Rule, now int64) Result {
+	window := time.Duration(rule.Window).Milliseconds()
+	timestamps := s.windows[key]
+	start := 0
+	for start < len(timestamps) && timestamps[start] <= now-window {
+		start++
+	}
+	timestamps = timestamps[start:]
+
+	allowed := int64(len(timestamps)) < rule.Limit
+	if allowed {
+		timestamps = append(timestamps, now)
+	}
+	s.windows[key] = timestamps
+	s.expiry[key] = now + window
+
+	reset := window
+	if len(timestamps) > 0 {
+		reset = timestamps[0] + window - now
+	}
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rule.Limit,
+		Remaining: rule.Limit - int64(len(timestamps)),
+		Reset:     time.Duration(reset) * time.Millisecond,
+	}
+	if !allowed {
+		result.RetryAfter = result.Reset
+	}
+	return result
+}
+
+// allowTokenBucket mirrors tokenBucketScript
+func (s *memoryStore) allowTokenBucket(key string, rule Rule, now int64) Result {
+	rate := refillRate(rule)
+	capacity := float64(rule.capacity())
+
+	state, exists := s.buckets[key]
+	if !exists {
+		state = &bucketState{tokens: capacity, ts: now}
+		s.buckets[key] = state
+	}
+	state.tokens = math.Min(capacity, state.tokens+math.Max(0, float64(now-state.ts))*rate)
+	state.ts = now
+
+	allowed := state.tokens >= 1
+	if allowed {
+		state.tokens--
+	}
+	reset := int64(math.Ceil((capacity - state.tokens) / rate))
+	s.expiry[key] = now + max(reset, 1)
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     rule.capacity(),
+		Remaining: int64(math.Floor(state.tokens)),
+		Reset:     time.Duration(reset) * time.Millisecond,
+	}
+	if !allowed {
+		result.RetryAfter = time.Duration(math.Ceil((1-state.tokens)/rate)) * time.Millisecond
+	}
+	return result
+}
+
+// sweep drops counters that have expired so idle clients do not accumulate
+func (s *memoryStore) sweep(now int64) {
+	if now-s.lastSweep < memorySweepInterval.Milliseconds() {
+		return
+	}
+	s.lastSweep = now
+
+	for key, expiry := range s.expiry {
+		if expiry <= now {
+			delete(s.windows, key)
+			delete(s.buckets, key)
+			delete(s.expiry, key)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// DefaultCacheBeta is the default weight of early probabilistic refresh
+	DefaultCacheBeta = 1.0
+
+	// DefaultCacheJitter is the default fraction of the TTL added at random
+	DefaultCacheJitter = 0.1
+
+	// DefaultLoadLockTimeout is the default lease of the cross-instance load lock
+	DefaultLoadLockTimeout = 10 * time.Second
+
+	// DefaultLoadLockWait is how long an instance waits for another one to load a value
+	DefaultLoadLockWait = 5 * time.Second
+
+	// loadLockPollInterval is how often a waiting instance checks for the loaded value
+	loadLockPollInterval = 50 * time.Millisecond
+)
+
+// Cache implements cache-aside loading on top of a Manager
+type Cache struct {
+	manager         *Manager
+	locker          *Locker
+	group           singleflight.Group
+	beta            float64
+	jitter          float64
+	negativeTTL     time.Duration
+	distributedLock bool
+	lockTimeout     time.Duration
+	lockWait        time.Duration
+}
+
+// CacheOption represents a function that configures the Cache
+type CacheOption func(*Cache)
+
+// WithEarlyRefresh sets the beta of early probabilistic refresh; zero disables it
+func WithEarlyRefresh(beta float64) CacheOption {
+	return func(c *Cache) {
+		c.beta = beta
+	}
+}
+
+// WithJitter adds up to fraction*ttl at random to every TTL; zero disables it
+func WithJitter(fraction float64) CacheOption {
+	return func(c *Cache) {
+		c.jitter = fraction
+	}
+}
+
+// WithNegativeCaching caches ErrNotFound results from loaders for ttl
+func WithNegativeCaching(ttl time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.negativeTTL = ttl
+	}
+}
+
+// WithDistributedLock coalesces misses across instances with a Redis lock
+func WithDistributedLock(timeout, wait time.Duration) CacheOption {
+	return func(c *Cache) {
+		c.distributedLock = true
+		c.lockTimeout = timeout
+		c.lockWait = wait
+	}
+}
+
+// NewCache creates a new cache-aside helper
+func NewCache(manager *Manager, opts ...CacheOption) *Cache {
+	cache := &Cache{
+		manager:     manager,
+		locker:      NewLocker(manager, WithFencing(false)),
+		beta:        DefaultCacheBeta,
+		jitter:      DefaultCacheJitter,
+		lockTimeout: DefaultLoadLockTimeout,
+		lockWait:    DefaultLoadLockWait,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(cache)
+	}
+
+	return cache
+}
+
+// cacheEntry is the envelope stored in Redis for cache-aside values
+type cacheEntry struct {
+	Value    json.RawMessage `json:"v,omitempty"`
+	Negative bool            `json:"n,omitempty"`
+	Delta    int64           `json:"d"`
+	Expiry   int64           `json:"e"`
+}
+
+// GetOrLoad returns the cached value for key or calls loader to produce it.
+// Concurrent misses in this process share a single loader call, and with
+// WithDistributedLock across instances too. Values close to expiry may be
+// refreshed early in the background.
+func GetOrLoad[T any](ctx context.Context, c *Cache, key string, ttl time.Duration, loader func(context.Context) (T, error)) (T, error) {
+	var zero T
+	load := func(ctx context.Context) ([]byte, error) {
+		value, err := loader(ctx)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value: %w", err)
+		}
+		return data, nil
+	}
+
+	entry, err := c.read(ctx, key)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		c.manager.logger.Warn("Cache read failed, loading directly", zap.String("key", key), zap.Error(err))
+	}
+
+	if err == nil && c.shouldRefreshEarly(entry) {
+		go c.refresh(context.WithoutCancel(ctx), key, ttl, load, entry)
+	}
+
+	if err != nil {
+		// The shared load must not depend on whichever caller started it, so
+		// it runs detached and every caller stops waiting on its own context
+		results := c.group.DoChan(key, func() (any, error) {
+			return c.load(context.WithoutCancel(ctx), key, ttl, load, nil)
+		})
+		select {
+		case result := <-results:
+			if result.Err != nil {
+				return zero, result.Err
+			}
+			entry = result.Val.(*cacheEntry)
+		case <-ctx.Done():
+			return zero, fmt.Errorf("failed to load key %s: %w", key, ctx.Err())
+		}
+	}
+
+	if entry.Negative {
+		return zero, fmt.Errorf("key %s: %w", key, ErrNotFound)
+	}
+
+	var value T
+	if err := json.Unmarshal(entry.Value, &value); err != nil {
+		return zero, fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
+	}
+	return value, nil
+}
+
+// read fetches and decodes a cache entry
+func (c *Cache) read(ctx context.Context, key string) (*cacheEntry, error) {
+	data, err := c.manager.client.Get(ctx, key).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cache entry for key %s: %w", key, err)
+	}
+	return &entry, nil
+}
+
+// write stores a cache entry with the given TTL
+func (c *Cache) write(ctx context.Context, key string, entry *cacheEntry, ttl time.Duration) error {
+	entry.Expiry = time.Now().Add(ttl).UnixMilli()
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache entry: %w", err)
+	}
+
+	if err := c.manager.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set key %s: %w", key, err)
+	}
+	return nil
+}
+
+// load runs the loader, optionally under a cross-instance lock, and caches the
+// result. stale is the entry being refreshed early, or nil on a miss.
+func (c *Cache) load(ctx context.Context, key string, ttl time.Duration, loader func(context.Context) ([]byte, error), stale *cacheEntry) (*cacheEntry, error) {
+	if c.distributedLock {
+		lock, err := c.locker.TryObtain(ctx, key+":lock", c.lockTimeout)
+		if err == nil {
+			defer c.releaseLoadLock(lock)
+
+			// Another instance may have stored the value before releasing the lock
+			if entry, err := c.read(ctx, key); err == nil && (stale == nil || entry.Expiry != stale.Expiry) {
+				return entry, nil
+			}
+		} else if !errors.Is(err, ErrLockNotObtained) {
+			c.manager.logger.Warn("Failed to acquire load lock", zap.String("key", key), zap.Error(err))
+		} else if entry, err := c.waitForValue(ctx, key); err == nil {
+			return entry, nil
+		}
+	}
+
+	start := time.Now()
+	data, err := loader(ctx)
+	delta := time.Since(start).Milliseconds()
+
+	if errors.Is(err, ErrNotFound) && c.negativeTTL > 0 {
+		entry := &cacheEntry{Negative: true, Delta: delta}
+		if err := c.write(ctx, key, entry, c.negativeTTL); err != nil {
+			c.manager.logger.Warn("Failed to cache negative result", zap.String("key", key), zap.Error(err))
+		}
+		return entry, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &cacheEntry{Value: data, Delta: delta}
+	if err := c.write(ctx, key, entry, c.jittered(ttl)); err != nil {
+		c.manager.logger.Warn("Failed to cache loaded value", zap.String("key", key), zap.Error(err))
+	}
+	return entry, nil
+}
+
+// refresh reloads a value ahead of its expiry, sharing the load with concurrent misses
+func (c *Cache) refresh(ctx context.Context, key string, ttl time.Duration, loader func(context.Context) ([]byte, error), stale *cacheEntry) {
+	_, err, _ := c.group.Do(key, func() (any, error) {
+		return c.load(ctx, key, ttl, loader, stale)
+	})
+	if err != nil {
+		c.manager.logger.Warn("Early cache refresh failed", zap.String("key", key), zap.Error(err))
+	}
+}
+
+// shouldRefreshEarly implements probabilistic early expiration (XFetch): the
+// closer an entry is to expiry and the slower it was to load, the more likely
+// a reader refreshes it
+func (c *Cache) shouldRefreshEarly(entry *cacheEntry) bool {
+	if c.beta <= 0 || entry.Negative || entry.Expiry == 0 {
+		return false
+	}
+	gap := -float64(entry.Delta) * c.beta * math.Log(mathrand.Float64())
+	return float64(time.Now().UnixMilli())+gap >= float64(entry.Expiry)
+}
+
+// jittered spreads expirations by adding a random fraction of the TTL
+func (c *Cache) jittered(ttl time.Duration) time.Duration {
+	if c.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	spread := int64(float64(ttl) * c.jitter)
+	if spread <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(mathrand.Int63n(spread))
+}
+
+// releaseLoadLock releases the load lock once the value has been cached
+func (c *Cache) releaseLoadLock(lock *Lock) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := lock.Release(ctx); err != nil {
+		c.manager.logger.Warn("Failed to release load lock", zap.String("key", lock.Key()), zap.Error(err))
+	}
+}
+
+// waitForValue polls for a value being loaded by another instance
+func (c *Cache) waitForValue(ctx context.Context, key string) (*cacheEntry, error) {
+	deadline := time.NewTimer(c.lockWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(loadLockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			entry, err := c.read(ctx, key)
+			if err == nil {
+				return entry, nil
+			}
+			if !errors.Is(err, redis.Nil) {
+				return nil, err
+			}
+		case <-deadline.C:
+			return nil, fmt.Errorf("timed out waiting for key %s to be loaded", key)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// codecMagic starts the format header of encoded values. It is never valid
+// UTF-8 and is unused by MessagePack, so headered values cannot be mistaken
+// for plain JSON written before codecs existed.
+const codecMagic byte = 0xC1
+
+// codecHeaderSize is the length of the magic, encoding and compression bytes
+const codecHeaderSize = 3
+
+// Encoding identifies how values are serialized
+type Encoding byte
+
+// Supported encodings
+const (
+	EncodingJSON     Encoding = 1
+	EncodingMsgPack  Encoding = 2
+	EncodingProtobuf Encoding = 3
+)
+
+// Compression identifies how serialized values are compressed
+type Compression byte
+
+// Supported compressions
+const (
+	CompressionNone Compression = 0
+	CompressionGzip Compression = 1
+	CompressionZstd Compression = 2
+)
+
+// Codec serializes values stored in Redis
+type Codec interface {
+	Marshal(value any) ([]byte, error)
+	Unmarshal(data []byte, value any) error
+}
+
+// DefaultCodec writes plain JSON, as values were stored before codecs existed
+var DefaultCodec Codec = NewCodec(EncodingJSON, CompressionNone, 0)
+
+// codec writes values with a format header and reads any supported format,
+// so data written with different codecs can be read during migrations
+type codec struct {
+	encoding    Encoding
+	compression Compression
+	threshold   int
+}
+
+// NewCodec creates a codec. Values whose serialized size reaches threshold
+// bytes are compressed; a zero threshold compresses every value. Uncompressed
+// JSON is written without a header so that older readers can still decode it.
+func NewCodec(encoding Encoding, compression Compression, threshold int) Codec {
+	return &codec{
+		encoding:    encoding,
+		compression: compression,
+		threshold:   threshold,
+	}
+}
+
+// Marshal implements Codec
+func (c *codec) Marshal(value any) ([]byte, error) {
+	data, err := marshalEncoding(c.encoding, value)
+	if err != nil {
+		return nil, err
+	}
+
+	compression := c.compression
+	if len(data) < c.threshold {
+		compression = CompressionNone
+	}
+	if c.encoding == EncodingJSON && compression == CompressionNone {
+		return data, nil
+	}
+
+	compressed, err := compress(compression, data)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{codecMagic, byte(c.encoding), byte(compression)}, compressed...), nil
+}
+
+// Unmarshal implements Codec. The format is taken from the header, not from
+// the codec's own settings; values without a header are read as JSON.
+func (c *codec) Unmarshal(data []byte, value any) error {
+	if len(data) < codecHeaderSize || data[0] != codecMagic {
+		return unmarshalEncoding(EncodingJSON, data, value)
+	}
+
+	decompressed, err := decompress(Compression(data[2]), data[codecHeaderSize:])
+	if err != nil {
+		return err
+	}
+	return unmarshalEncoding(Encoding(data[1]), decompressed, value)
+}
+
+// marshalEncoding serializes a value
+func marshalEncoding(encoding Encoding, value any) ([]byte, error) {
+	switch encoding {
+	case EncodingJSON:
+		return json.Marshal(value)
+	case EncodingMsgPack:
+		return msgpack.Marshal(value)
+	case EncodingProtobuf:
+		message, ok := value.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("protobuf codec requires a proto.Message, got %T", value)
+		}
+		return proto.Marshal(message)
+	default:
+		return nil, fmt.Errorf("unsupported encoding %d", encoding)
+	}
+}
+
+// unmarshalEncoding deserializes a value
+func unmarshalEncoding(encoding Encoding, data []byte, value any) error {
+	switch encoding {
+	case EncodingJSON:
+		return json.Unmarshal(data, value)
+	case EncodingMsgPack:
+		return msgpack.Unmarshal(data, value)
+	case EncodingProtobuf:
+		message, err := protoTarget(value)
+		if err != nil {
+			return err
+		}
+		return proto.Unmarshal(data, message)
+	default:
+		return fmt.Errorf("unsupported encoding %d", encoding)
+	}
+}
+
+// protoTarget returns the message to unmarshal into. Besides a message it
+// accepts a pointer to a message pointer, as passed by Store[*pb.Message],
+// allocating the message when the pointer is nil.
+func protoTarget(value any) (proto.Message, error) {
+	if message, ok := value.(proto.Message); ok {
+		return message, nil
+	}
+
+	target := reflect.ValueOf(value)
+	if target.Kind() == reflect.Pointer && !target.IsNil() && target.Elem().Kind() == reflect.Pointer {
+		elem := target.Elem()
+		if _, ok := elem.Interface().(proto.Message); ok {
+			if elem.IsNil() {
+				elem.Set(reflect.New(elem.Type().Elem()))
+			}
+			return elem.Interface().(proto.Message), nil
+		}
+	}
+	return nil, fmt.Errorf("protobuf codec requires a proto.Message, got %T", value)
+}
+
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil)
+	})
+)
+
+// compress compresses serialized data
+func compress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		writer := gzip.NewWriter(&buf)
+		if _, err := writer.Write(data); err != nil {
+			return nil, fmt.Errorf("failed to gzip value: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to gzip value: %w", err)
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		return encoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %d", compression)
+	}
+}
+
+// decompress reverses compress
+func decompress(compression Compression, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to gunzip value: %w", err)
+		}
+		defer reader.Close()
+		decompressed, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to gunzip value: %w", err)
+		}
+		return decompressed, nil
+	case CompressionZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		decompressed, err := decoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress zstd value: %w", err)
+		}
+		return decompressed, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression %d", compression)
+	}
+}
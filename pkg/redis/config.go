@@ -1,66 +1,340 @@
 package redis
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"time"
 
 	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 )
 
+const (
+	// configKey is the ZooKeeper config key holding the Redis settings
+	configKey = "REDIS_CONFIG"
+
+	// meterName is the OpenTelemetry instrumentation scope of this package
+	meterName = "github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+)
+
+// Mode identifies the Redis deployment topology
+type Mode string
+
+// Supported modes
+const (
+	ModeStandalone Mode = "standalone"
+	ModeSentinel   Mode = "sentinel"
+	ModeCluster    Mode = "cluster"
+)
+
 // Config represents Redis configuration
 type Config struct {
 	Host string
 	Port int
+
+	// Mode selects the topology. When empty it is sentinel if MasterName is
+	// set, cluster if more than one address is given and standalone otherwise.
+	Mode Mode
+
+	// Addrs lists cluster nodes or Sentinel addresses; Host and Port are used when empty
+	Addrs []string
+
+	// MasterName is the Sentinel master set name
+	MasterName       string
+	SentinelUsername string
+	SentinelPassword string
+
+	Username string
+	Password string
+	DB       int
+
+	// TLS enables TLS when non-nil
+	TLS *tls.Config
+
+	PoolSize        int
+	MinIdleConns    int
+	MaxIdleConns    int
+	PoolTimeout     time.Duration
+	ConnMaxIdleTime time.Duration
+	DialTimeout     time.Duration
+	ReadTimeout     time.Duration
+	WriteTimeout    time.Duration
+	MaxRetries      int
+
+	// SlowThreshold is the duration above which commands are logged; zero disables the log
+	SlowThreshold time.Duration
+
+	// Redaction controls how much of a command appears in spans and logs, RedactValues when empty
+	Redaction Redaction
 }
 
 // NewConfig creates a new Redis configuration from ZooKeeper
 func NewConfig(zkClient *zookeeper.Client, logger *zap.Logger) (*Config, error) {
-	redisConfig, err := zkClient.GetConfigValueByKey("REDIS_CONFIG", true)
+	redisConfig, err := zkClient.GetConfigValueByKey(configKey, true)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get Redis config from ZooKeeper: %w", err)
 	}
 
-	configMap, ok := redisConfig.(map[string]any)
-	if !ok {
+	if _, ok := redisConfig.(map[string]any); !ok {
 		return nil, fmt.Errorf("invalid Redis config format")
 	}
 
-	host, ok := configMap["host"].(string)
-	if !ok {
-		host = "localhost"
-		logger.Warn("Using default Redis host: localhost")
+	reader := &configReader{zkClient: zkClient}
+	config := &Config{
+		Mode:             Mode(reader.string("mode")),
+		Addrs:            reader.stringSlice("addrs"),
+		MasterName:       reader.string("masterName"),
+		SentinelUsername: reader.string("sentinelUsername"),
+		SentinelPassword: reader.string("sentinelPassword"),
+		Username:         reader.string("username"),
+		Password:         reader.string("password"),
+		DB:               reader.int("db"),
+		PoolSize:         reader.int("poolSize"),
+		MinIdleConns:     reader.int("minIdleConns"),
+		MaxIdleConns:     reader.int("maxIdleConns"),
+		PoolTimeout:      reader.duration("poolTimeout"),
+		ConnMaxIdleTime:  reader.duration("connMaxIdleTime"),
+		DialTimeout:      reader.duration("dialTimeout"),
+		ReadTimeout:      reader.duration("readTimeout"),
+		WriteTimeout:     reader.duration("writeTimeout"),
+		MaxRetries:       reader.int("maxRetries"),
+		SlowThreshold:    DefaultSlowThreshold,
+		Redaction:        Redaction(reader.string("redaction")),
 	}
 
-	portStr, ok := configMap["port"].(string)
-	if !ok {
-		portStr = "6379"
-		logger.Warn("Using default Redis port: 6379")
+	if reader.has("slowThreshold") {
+		config.SlowThreshold = reader.duration("slowThreshold")
 	}
 
-	port, err := strconv.Atoi(portStr)
-	if err != nil {
-		return nil, fmt.Errorf("invalid Redis port: %w", err)
+	if len(config.Addrs) == 0 {
+		config.Host = reader.string("host")
+		if config.Host == "" {
+			config.Host = "localhost"
+			logger.Warn("Using default Redis host: localhost")
+		}
+
+		config.Port = reader.int("port")
+		if config.Port == 0 {
+			config.Port = 6379
+			logger.Warn("Using default Redis port: 6379")
+		}
+	}
+
+	if reader.bool("tls.enabled") {
+		config.TLS, err = newTLSConfig(
+			reader.string("tls.serverName"),
+			reader.string("tls.caFile"),
+			reader.string("tls.certFile"),
+			reader.string("tls.keyFile"),
+			reader.bool("tls.insecureSkipVerify"),
+		)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	return &Config{
-		Host: host,
-		Port: port,
-	}, nil
+	if err := errors.Join(reader.errs...); err != nil {
+		return nil, fmt.Errorf("invalid Redis config: %w", err)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid Redis config: %w", err)
+	}
+
+	return config, nil
+}
+
+// Addresses returns the addresses to connect to
+func (c *Config) Addresses() []string {
+	if len(c.Addrs) > 0 {
+		return c.Addrs
+	}
+	return []string{fmt.Sprintf("%s:%d", c.Host, c.Port)}
+}
+
+// ResolvedMode returns the configured mode, inferring it when unset
+func (c *Config) ResolvedMode() Mode {
+	switch {
+	case c.Mode != "":
+		return c.Mode
+	case c.MasterName != "":
+		return ModeSentinel
+	case len(c.Addrs) > 1:
+		return ModeCluster
+	default:
+		return ModeStandalone
+	}
 }
 
-// NewClient creates a new Redis client
-func NewClient(config *Config, logger *zap.Logger) *redis.Client {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Password: "", // no password set
-		DB:       0,  // use default DB
-	})
+// validate checks that a configuration can be used to connect
+func (c *Config) validate() error {
+	switch c.ResolvedMode() {
+	case ModeStandalone, ModeCluster:
+	case ModeSentinel:
+		if c.MasterName == "" {
+			return fmt.Errorf("sentinel mode requires a master name")
+		}
+	default:
+		return fmt.Errorf("unsupported Redis mode %q", c.Mode)
+	}
+	if c.DB != 0 && c.ResolvedMode() == ModeCluster {
+		return fmt.Errorf("cluster mode only supports DB 0")
+	}
+	switch c.Redaction {
+	case "", RedactNone, RedactValues, RedactAll:
+	default:
+		return fmt.Errorf("unsupported redaction %q", c.Redaction)
+	}
+	return nil
+}
+
+// NewClient creates a new Redis client for the configured topology, traced
+// and measured through OpenTelemetry
+func NewClient(config *Config, logger *zap.Logger) redis.UniversalClient {
+	options := &redis.UniversalOptions{
+		Addrs:            config.Addresses(),
+		MasterName:       config.MasterName,
+		SentinelUsername: config.SentinelUsername,
+		SentinelPassword: config.SentinelPassword,
+		Username:         config.Username,
+		Password:         config.Password,
+		DB:               config.DB,
+		TLSConfig:        config.TLS,
+		PoolSize:         config.PoolSize,
+		MinIdleConns:     config.MinIdleConns,
+		MaxIdleConns:     config.MaxIdleConns,
+		PoolTimeout:      config.PoolTimeout,
+		ConnMaxIdleTime:  config.ConnMaxIdleTime,
+		DialTimeout:      config.DialTimeout,
+		ReadTimeout:      config.ReadTimeout,
+		WriteTimeout:     config.WriteTimeout,
+		MaxRetries:       config.MaxRetries,
+	}
+
+	mode := config.ResolvedMode()
+	var client redis.UniversalClient
+	switch mode {
+	case ModeSentinel:
+		client = redis.NewFailoverClient(options.Failover())
+	case ModeCluster:
+		client = redis.NewClusterClient(options.Cluster())
+	default:
+		client = redis.NewClient(options.Simple())
+	}
+	client.AddHook(newInstrumentation(config, logger))
 
 	logger.Info("Redis client initialized",
-		zap.String("host", config.Host),
-		zap.Int("port", config.Port))
+		zap.String("mode", string(mode)),
+		zap.Strings("addrs", options.Addrs),
+		zap.Int("db", config.DB),
+		zap.Bool("tls", config.TLS != nil))
 
 	return client
 }
+
+// newTLSConfig builds the TLS settings, loading an optional CA bundle and client certificate
+func newTLSConfig(serverName, caFile, certFile, keyFile string, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: insecureSkipVerify,
+	}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read Redis CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in Redis CA file %s", caFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load Redis client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+// configReader reads optional REDIS_CONFIG fields, collecting invalid values
+type configReader struct {
+	zkClient *zookeeper.Client
+	errs     []error
+}
+
+// has reports whether a field is set
+func (r *configReader) has(field string) bool {
+	_, err := r.zkClient.GetConfigValueByKey(configKey+"."+field, true)
+	return err == nil
+}
+
+// string reads a string field, returning "" when it is missing
+func (r *configReader) string(field string) string {
+	if !r.has(field) {
+		return ""
+	}
+	value, err := r.zkClient.GetStringValueByKey(configKey+"."+field, true)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return value
+}
+
+// int reads an int field, returning 0 when it is missing
+func (r *configReader) int(field string) int {
+	if !r.has(field) {
+		return 0
+	}
+	value, err := r.zkClient.GetInt(configKey+"."+field, true)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return value
+}
+
+// bool reads a bool field, returning false when it is missing
+func (r *configReader) bool(field string) bool {
+	if !r.has(field) {
+		return false
+	}
+	value, err := r.zkClient.GetBool(configKey+"."+field, true)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return value
+}
+
+// duration reads a duration field, returning 0 when it is missing
+func (r *configReader) duration(field string) time.Duration {
+	if !r.has(field) {
+		return 0
+	}
+	value, err := r.zkClient.GetDuration(configKey+"."+field, true)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return value
+}
+
+// stringSlice reads a list field, returning nil when it is missing
+func (r *configReader) stringSlice(field string) []string {
+	if !r.has(field) {
+		return nil
+	}
+	value, err := r.zkClient.GetStringSlice(configKey+"."+field, true)
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+	return value
+}
@@ -0,0 +1,31 @@
+package redis
+
+import "errors"
+
+// ErrNotFound reports that a key or the entity it caches does not exist. Loaders
+// passed to GetOrLoad return it to have the absence cached.
+var ErrNotFound = errors.New("not found")
+
+// ErrDecode reports that a stored value exists but could not be decoded
+var ErrDecode = errors.New("decode failed")
+
+var (
+	// ErrLockNotObtained is returned when a lock is held by someone else
+	ErrLockNotObtained = errors.New("lock not obtained")
+
+	// ErrLockNotHeld is returned when releasing or refreshing a lock that has
+	// expired or been taken over
+	ErrLockNotHeld = errors.New("lock not held")
+)
+
+var (
+	// ErrUnknownScript is returned when running a script that was not registered
+	ErrUnknownScript = errors.New("unknown script")
+
+	// ErrTxConflict is returned when a transaction keeps failing because its
+	// watched keys were modified concurrently
+	ErrTxConflict = errors.New("transaction conflict")
+
+	// ErrNotExecuted is returned by a pipeline result read before the pipeline ran
+	ErrNotExecuted = errors.New("pipeline not executed")
+)
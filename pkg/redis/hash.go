@@ -0,0 +1,123 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Hash fields are always stored as JSON, one field per top-level property of
+// the value, so that numeric fields remain usable with HIncrBy regardless of
+// the manager's codec.
+
+// HSet writes the properties of a struct or map as fields of a hash, keeping
+// fields that are not part of the value
+func (m *Manager) HSet(ctx context.Context, key string, value any) error {
+	fields, err := hashFields(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal hash %s: %w", key, err)
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	if err := m.client.HSet(ctx, key, fields).Err(); err != nil {
+		return fmt.Errorf("failed to set hash %s: %w", key, err)
+	}
+	return nil
+}
+
+// HSetField sets a single field of a hash
+func (m *Manager) HSetField(ctx context.Context, key, field string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal field %s of hash %s: %w", field, key, err)
+	}
+
+	if err := m.client.HSet(ctx, key, field, data).Err(); err != nil {
+		return fmt.Errorf("failed to set field %s of hash %s: %w", field, key, err)
+	}
+	return nil
+}
+
+// HGetAll reads every field of a hash into a struct or map.
+// It returns ErrNotFound if the hash does not exist.
+func (m *Manager) HGetAll(ctx context.Context, key string, value any) error {
+	fields, err := m.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get hash %s: %w", key, err)
+	}
+	if len(fields) == 0 {
+		return fmt.Errorf("hash %s: %w", key, ErrNotFound)
+	}
+
+	object := make(map[string]json.RawMessage, len(fields))
+	for field, data := range fields {
+		object[field] = json.RawMessage(data)
+	}
+	data, err := json.Marshal(object)
+	if err == nil {
+		err = json.Unmarshal(data, value)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal hash %s: %w: %w", key, ErrDecode, err)
+	}
+	return nil
+}
+
+// HGetField reads a single field of a hash.
+// It returns ErrNotFound if the field does not exist.
+func (m *Manager) HGetField(ctx context.Context, key, field string, value any) error {
+	data, err := m.client.HGet(ctx, key, field).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("field %s of hash %s: %w", field, key, ErrNotFound)
+		}
+		return fmt.Errorf("failed to get field %s of hash %s: %w", field, key, err)
+	}
+
+	if err := json.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to unmarshal field %s of hash %s: %w: %w", field, key, ErrDecode, err)
+	}
+	return nil
+}
+
+// HDelete removes fields from a hash and returns how many existed
+func (m *Manager) HDelete(ctx context.Context, key string, fields ...string) (int64, error) {
+	result, err := m.client.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete fields of hash %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// HIncrBy increments a numeric field of a hash
+func (m *Manager) HIncrBy(ctx context.Context, key, field string, increment int64) (int64, error) {
+	result, err := m.client.HIncrBy(ctx, key, field, increment).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment field %s of hash %s: %w", field, key, err)
+	}
+	return result, nil
+}
+
+// hashFields flattens a value into its top-level JSON properties
+func hashFields(value any) (map[string]any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("value of type %T is not an object", value)
+	}
+
+	fields := make(map[string]any, len(object))
+	for field, raw := range object {
+		fields[field] = []byte(raw)
+	}
+	return fields, nil
+}
@@ -0,0 +1,291 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultSlowThreshold is the default duration above which commands are logged as slow
+	DefaultSlowThreshold = 100 * time.Millisecond
+
+	// maxStatementLength bounds the length of a command rendered into spans and logs
+	maxStatementLength = 512
+
+	// redactedArg replaces redacted command arguments
+	redactedArg = "?"
+)
+
+// Redaction controls how much of a command is exposed in spans and logs
+type Redaction string
+
+// Supported redaction levels
+const (
+	// RedactNone exposes commands with all their arguments
+	RedactNone Redaction = "none"
+
+	// RedactValues exposes command names and keys but hides values
+	RedactValues Redaction = "values"
+
+	// RedactAll exposes command names only
+	RedactAll Redaction = "all"
+)
+
+// instrumentation is a go-redis hook that traces commands, records their
+// latency and errors and logs slow ones
+type instrumentation struct {
+	tracer        trace.Tracer
+	duration      metric.Float64Histogram
+	errors        metric.Int64Counter
+	logger        *zap.Logger
+	slowThreshold time.Duration
+	redaction     Redaction
+	baseAttrs     []attribute.KeyValue
+}
+
+// newInstrumentation creates the instrumentation hook for a client
+func newInstrumentation(config *Config, logger *zap.Logger) *instrumentation {
+	meter := otel.Meter(meterName)
+	duration, err := meter.Float64Histogram("redis.command.duration",
+		metric.WithDescription("Duration of Redis commands and pipelines"),
+		metric.WithUnit("s"))
+	if err != nil {
+		logger.Warn("Failed to create Redis command duration histogram", zap.Error(err))
+	}
+	errorCount, err := meter.Int64Counter("redis.command.errors",
+		metric.WithDescription("Redis commands and pipelines that failed"))
+	if err != nil {
+		logger.Warn("Failed to create Redis command error counter", zap.Error(err))
+	}
+
+	redaction := config.Redaction
+	if redaction == "" {
+		redaction = RedactValues
+	}
+
+	return &instrumentation{
+		tracer:        otel.Tracer(meterName),
+		duration:      duration,
+		errors:        errorCount,
+		logger:        logger,
+		slowThreshold: config.SlowThreshold,
+		redaction:     redaction,
+		baseAttrs: []attribute.KeyValue{
+			attribute.String("db.system", "redis"),
+			attribute.String("db.redis.mode", string(config.ResolvedMode())),
+		},
+	}
+}
+
+// DialHook implements redis.Hook
+func (i *instrumentation) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// ProcessHook implements redis.Hook
+func (i *instrumentation) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		operation := cmd.Name()
+		ctx, span := i.startSpan(ctx, operation, attribute.String("db.operation", operation))
+		defer span.End()
+
+		// Rendering the statement is only worth it for sampled spans
+		if span.IsRecording() {
+			span.SetAttributes(attribute.String("db.statement", i.statement(cmd)))
+		}
+
+		start := time.Now()
+		err := next(ctx, cmd)
+		failure := err
+		if failure == nil {
+			failure = cmd.Err()
+		}
+		i.finish(ctx, span, operation, time.Since(start), failure, isBlocking(cmd), func() string {
+			return i.statement(cmd)
+		})
+		return err
+	}
+}
+
+// ProcessPipelineHook implements redis.Hook
+func (i *instrumentation) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		names := make([]string, len(cmds))
+		blocking := false
+		for n, cmd := range cmds {
+			names[n] = cmd.Name()
+			blocking = blocking || isBlocking(cmd)
+		}
+
+		ctx, span := i.startSpan(ctx, "pipeline",
+			attribute.String("db.operation", "pipeline"),
+			attribute.Int("db.redis.num_cmd", len(cmds)),
+			attribute.StringSlice("db.redis.commands", names))
+		defer span.End()
+
+		start := time.Now()
+		err := next(ctx, cmds)
+		failure := err
+		if failure == nil || errors.Is(failure, redis.Nil) {
+			failure = firstCmdError(cmds)
+		}
+		i.finish(ctx, span, "pipeline", time.Since(start), failure, blocking, func() string {
+			statements := make([]string, len(cmds))
+			for n, cmd := range cmds {
+				statements[n] = i.statement(cmd)
+			}
+			return truncate(strings.Join(statements, "; "))
+		})
+		return err
+	}
+}
+
+// startSpan starts a client span for a command or pipeline
+func (i *instrumentation) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return i.tracer.Start(ctx, "redis."+name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(i.baseAttrs...),
+		trace.WithAttributes(attrs...))
+}
+
+// finish records the outcome of a command or pipeline. A missing key is not
+// treated as an error, and blocking commands are never logged as slow since
+// they are expected to wait.
+func (i *instrumentation) finish(ctx context.Context, span trace.Span, operation string, elapsed time.Duration, err error, blocking bool, statement func() string) {
+	failed := err != nil && !errors.Is(err, redis.Nil)
+	attrs := metric.WithAttributes(attribute.String("command", operation))
+
+	if i.duration != nil {
+		i.duration.Record(ctx, elapsed.Seconds(), attrs)
+	}
+	if failed {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if i.errors != nil {
+			i.errors.Add(ctx, 1, attrs)
+		}
+	}
+
+	if i.slowThreshold > 0 && !blocking && elapsed >= i.slowThreshold {
+		fields := []zap.Field{
+			zap.String("command", statement()),
+			zap.Duration("duration", elapsed),
+		}
+		if failed {
+			fields = append(fields, zap.Error(err))
+		}
+		i.logger.Warn("Slow Redis command", fields...)
+	}
+}
+
+// statement renders a command for spans and logs according to the redaction level
+func (i *instrumentation) statement(cmd redis.Cmder) string {
+	args := cmd.Args()
+	if len(args) == 0 {
+		return cmd.Name()
+	}
+
+	// Credentials are never exposed
+	switch cmd.Name() {
+	case "auth", "hello":
+		return cmd.Name()
+	}
+
+	switch i.redaction {
+	case RedactAll:
+		return cmd.Name()
+	case RedactNone:
+		parts := make([]string, len(args))
+		for n, arg := range args {
+			parts[n] = fmt.Sprint(arg)
+		}
+		return truncate(strings.Join(parts, " "))
+	default:
+		parts := make([]string, len(args))
+		keyPos := keyPosition(cmd)
+		for n, arg := range args {
+			// The name, subcommands and key counts precede the key and are not values
+			if n <= keyPos {
+				parts[n] = fmt.Sprint(arg)
+			} else {
+				parts[n] = redactedArg
+			}
+		}
+		return truncate(strings.Join(parts, " "))
+	}
+}
+
+// keyPosition returns the argument index of a command's first key, or 0 if
+// it has none
+func keyPosition(cmd redis.Cmder) int {
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		if len(args) > 3 && fmt.Sprint(args[2]) != "0" {
+			return 3
+		}
+		return 0
+	case "ping", "info", "hello", "auth", "select", "client", "config", "script",
+		"function", "dbsize", "flushdb", "flushall", "time", "scan", "cluster", "command":
+		return 0
+	case "memory", "object":
+		return 2
+	}
+	if len(args) > 1 {
+		return 1
+	}
+	return 0
+}
+
+// isBlocking reports whether a command waits on the server for data, such as
+// BLPOP or XREADGROUP with BLOCK
+func isBlocking(cmd redis.Cmder) bool {
+	switch cmd.Name() {
+	case "blpop", "brpop", "brpoplpush", "blmove", "blmpop", "bzpopmin", "bzpopmax", "bzmpop":
+		return true
+	case "xread", "xreadgroup":
+		// Options precede STREAMS, after which stream names could read "block"
+		for _, arg := range cmd.Args() {
+			name, _ := arg.(string)
+			switch strings.ToLower(name) {
+			case "block":
+				return true
+			case "streams":
+				return false
+			}
+		}
+	}
+	return false
+}
+
+// firstCmdError returns the first error of a pipeline other than a missing key
+func firstCmdError(cmds []redis.Cmder) error {
+	for _, cmd := range cmds {
+		if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+	}
+	return nil
+}
+
+// truncate bounds the length of a rendered statement
+func truncate(statement string) string {
+	if len(statement) <= maxStatementLength {
+		return statement
+	}
+	return statement[:maxStatementLength] + "..."
+}
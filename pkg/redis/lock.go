@@ -0,0 +1,321 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultLockRetryInterval is the default delay between acquisition attempts
+	DefaultLockRetryInterval = 100 * time.Millisecond
+
+	// DefaultFenceTTL is the default time a fencing counter outlives the last
+	// acquisition of its lock
+	DefaultFenceTTL = 24 * time.Hour
+
+	// fenceKeySuffix names the counter that issues fencing tokens for a lock
+	fenceKeySuffix = ":fence"
+)
+
+var (
+	// obtainLockScript sets the lock if it is free and issues the next fencing
+	// token. A missing counter is seeded with the server time in microseconds,
+	// so tokens keep increasing after the counter expires or is lost.
+	obtainLockScript = redis.NewScript(`
+if not redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return 0
+end
+if redis.call("EXISTS", KEYS[2]) == 0 then
+	local now = redis.call("TIME")
+	redis.call("SET", KEYS[2], now[1] .. string.format("%06d", now[2]))
+end
+local fence = redis.call("INCR", KEYS[2])
+redis.call("PEXPIRE", KEYS[2], ARGV[3])
+return fence
+`)
+
+	// releaseLockScript deletes the lock only if it still holds the caller's token
+	releaseLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+	// extendLockScript renews the lease only if the lock still holds the caller's token
+	extendLockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+)
+
+// Locker hands out distributed locks stored in Redis
+type Locker struct {
+	manager       *Manager
+	retryInterval time.Duration
+	autoExtend    bool
+	fencing       bool
+	fenceTTL      time.Duration
+}
+
+// LockOption represents a function that configures the Locker
+type LockOption func(*Locker)
+
+// WithRetryInterval sets the delay between acquisition attempts in Obtain
+func WithRetryInterval(interval time.Duration) LockOption {
+	return func(l *Locker) {
+		l.retryInterval = interval
+	}
+}
+
+// WithAutoExtend controls whether held locks renew their lease in the background
+func WithAutoExtend(enabled bool) LockOption {
+	return func(l *Locker) {
+		l.autoExtend = enabled
+	}
+}
+
+// WithFencing controls whether locks are issued fencing tokens. Fencing keeps
+// a counter key per lock name for the fence TTL, so disable it for locks on
+// unbounded key spaces, such as per-request or per-entity keys, whose holders
+// do not need FencingToken.
+func WithFencing(enabled bool) LockOption {
+	return func(l *Locker) {
+		l.fencing = enabled
+	}
+}
+
+// WithFenceTTL sets how long a fencing counter is kept after the last
+// acquisition of its lock
+func WithFenceTTL(ttl time.Duration) LockOption {
+	return func(l *Locker) {
+		l.fenceTTL = ttl
+	}
+}
+
+// NewLocker creates a new Redis locker
+func NewLocker(manager *Manager, opts ...LockOption) *Locker {
+	locker := &Locker{
+		manager:       manager,
+		retryInterval: DefaultLockRetryInterval,
+		autoExtend:    true,
+		fencing:       true,
+		fenceTTL:      DefaultFenceTTL,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(locker)
+	}
+
+	return locker
+}
+
+// Lock is a held Redis lock
+type Lock struct {
+	locker  *Locker
+	key     string
+	lockKey string
+	token   string
+	fence   int64
+	ttl     time.Duration
+	ctx     context.Context
+	cancel  context.CancelFunc
+	stopped chan struct{}
+	once    sync.Once
+}
+
+// Obtain blocks until the lock is acquired or ctx is done
+func (l *Locker) Obtain(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(l.retryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryObtain(ctx, key, ttl)
+		if !errors.Is(err, ErrLockNotObtained) {
+			return lock, err
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to obtain lock %s: %w", key, ctx.Err())
+		}
+	}
+}
+
+// TryObtain attempts to acquire the lock once, returning ErrLockNotObtained if it is held elsewhere
+func (l *Locker) TryObtain(ctx context.Context, key string, ttl time.Duration) (*Lock, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey := lockKeyFor(key)
+	start := time.Now()
+	obtained, fence, err := l.obtain(ctx, lockKey, token, ttl)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain lock %s: %w", key, err)
+	}
+	if !obtained {
+		return nil, fmt.Errorf("lock %s: %w", key, ErrLockNotObtained)
+	}
+
+	lockCtx, cancel := context.WithCancel(context.Background())
+	lock := &Lock{
+		locker:  l,
+		key:     key,
+		lockKey: lockKey,
+		token:   token,
+		fence:   fence,
+		ttl:     ttl,
+		ctx:     lockCtx,
+		cancel:  cancel,
+		stopped: make(chan struct{}),
+	}
+	if l.autoExtend {
+		go lock.keepAlive(start)
+	}
+	return lock, nil
+}
+
+// obtain sets the lock key if it is free, issuing a fencing token when fencing is enabled
+func (l *Locker) obtain(ctx context.Context, lockKey, token string, ttl time.Duration) (bool, int64, error) {
+	if !l.fencing {
+		obtained, err := l.manager.client.SetNX(ctx, lockKey, token, ttl).Result()
+		return obtained, 0, err
+	}
+
+	fence, err := obtainLockScript.Run(ctx, l.manager.client,
+		[]string{lockKey, lockKey + fenceKeySuffix}, token, ttl.Milliseconds(), l.fenceTTL.Milliseconds()).Int64()
+	return fence > 0, fence, err
+}
+
+// Key returns the name the lock was obtained with
+func (lk *Lock) Key() string {
+	return lk.key
+}
+
+// Token returns the owner token stored in the lock key
+func (lk *Lock) Token() string {
+	return lk.token
+}
+
+// FencingToken returns a number that increases with every acquisition of this
+// lock. Pass it to downstream writes so stale holders can be rejected. Tokens
+// are seeded from the Redis server time, so they also increase across expiry
+// of the fencing counter. It returns 0 when fencing is disabled.
+func (lk *Lock) FencingToken() int64 {
+	return lk.fence
+}
+
+// Context returns a context that is cancelled when the lock is released or lost
+func (lk *Lock) Context() context.Context {
+	return lk.ctx
+}
+
+// Lost returns a channel that is closed when the lock is released or lost
+func (lk *Lock) Lost() <-chan struct{} {
+	return lk.ctx.Done()
+}
+
+// Refresh extends the lease to ttl, returning ErrLockNotHeld if the lock was lost
+func (lk *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	result, err := extendLockScript.Run(ctx, lk.locker.manager.client,
+		[]string{lk.lockKey}, lk.token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to refresh lock %s: %w", lk.key, err)
+	}
+	if result == 0 {
+		lk.stop()
+		return fmt.Errorf("lock %s: %w", lk.key, ErrLockNotHeld)
+	}
+	return nil
+}
+
+// Release frees the lock, returning ErrLockNotHeld if it had already expired
+// or been taken over
+func (lk *Lock) Release(ctx context.Context) error {
+	lk.stop()
+
+	result, err := releaseLockScript.Run(ctx, lk.locker.manager.client,
+		[]string{lk.lockKey}, lk.token).Int64()
+	if err != nil {
+		return fmt.Errorf("failed to release lock %s: %w", lk.key, err)
+	}
+	if result == 0 {
+		return fmt.Errorf("lock %s: %w", lk.key, ErrLockNotHeld)
+	}
+	return nil
+}
+
+// stop ends the keep-alive loop and cancels the lock context
+func (lk *Lock) stop() {
+	lk.once.Do(func() {
+		close(lk.stopped)
+		lk.cancel()
+	})
+}
+
+// keepAlive renews the lease at a third of the TTL until the lock is released
+// or lost. The lock counts as lost once a full TTL has passed since the lease
+// was last granted, as it may have expired while Redis was unreachable.
+func (lk *Lock) keepAlive(granted time.Time) {
+	ticker := time.NewTicker(lk.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			start := time.Now()
+			ctx, cancel := context.WithTimeout(context.Background(), lk.ttl/3)
+			err := lk.Refresh(ctx, lk.ttl)
+			cancel()
+			switch {
+			case err == nil:
+				granted = start
+			case errors.Is(err, ErrLockNotHeld):
+				lk.locker.manager.logger.Warn("Lock lost", zap.String("key", lk.key))
+				return
+			case time.Since(granted) >= lk.ttl:
+				lk.locker.manager.logger.Warn("Lock lost", zap.String("key", lk.key), zap.Error(err))
+				lk.stop()
+				return
+			default:
+				lk.locker.manager.logger.Warn("Failed to extend lock", zap.String("key", lk.key), zap.Error(err))
+			}
+		case <-lk.stopped:
+			return
+		}
+	}
+}
+
+// lockKeyFor returns the key holding a lock. Keys without a hash tag are wrapped
+// in one so that the lock and its fence counter map to the same slot in
+// cluster mode.
+func lockKeyFor(key string) string {
+	if !strings.Contains(key, "{") {
+		key = "{" + key + "}"
+	}
+	return key
+}
+
+// newToken generates a random owner token
+func newToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
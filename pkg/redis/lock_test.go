@@ -0,0 +1,247 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestLocker creates a locker backed by an in-memory Redis server
+func newTestLocker(t *testing.T, opts ...LockOption) (*Locker, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewLocker(NewManager(client, zap.NewNop()), opts...), server
+}
+
+func TestLockObtainAndRelease(t *testing.T) {
+	locker, server := newTestLocker(t, WithAutoExtend(false))
+	ctx := context.Background()
+
+	lock, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+	if got := server.TTL("{orders}"); got != time.Second {
+		t.Errorf("lock TTL = %v, want %v", got, time.Second)
+	}
+
+	if _, err := locker.TryObtain(ctx, "orders", time.Second); !errors.Is(err, ErrLockNotObtained) {
+		t.Fatalf("TryObtain() on held lock error = %v, want ErrLockNotObtained", err)
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	select {
+	case <-lock.Lost():
+	default:
+		t.Error("Lost() not closed after Release()")
+	}
+
+	again, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() after release error = %v", err)
+	}
+	again.Release(ctx)
+}
+
+func TestLockReleaseByNonOwner(t *testing.T) {
+	locker, server := newTestLocker(t, WithAutoExtend(false))
+	ctx := context.Background()
+
+	stale, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+
+	// Let the lease expire and have another holder take the lock over
+	server.FastForward(2 * time.Second)
+	owner, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() after expiry error = %v", err)
+	}
+
+	if err := stale.Release(ctx); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("Release() by non-owner error = %v, want ErrLockNotHeld", err)
+	}
+	if err := stale.Refresh(ctx, time.Second); !errors.Is(err, ErrLockNotHeld) {
+		t.Fatalf("Refresh() by non-owner error = %v, want ErrLockNotHeld", err)
+	}
+	if got, _ := server.Get("{orders}"); got != owner.Token() {
+		t.Fatalf("lock token = %q, want the new owner's token %q", got, owner.Token())
+	}
+
+	if err := owner.Release(ctx); err != nil {
+		t.Fatalf("Release() by owner error = %v", err)
+	}
+}
+
+func TestLockRefresh(t *testing.T) {
+	locker, server := newTestLocker(t, WithAutoExtend(false))
+	ctx := context.Background()
+
+	lock, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+	defer lock.Release(ctx)
+
+	if err := lock.Refresh(ctx, 10*time.Second); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if got := server.TTL("{orders}"); got != 10*time.Second {
+		t.Errorf("lock TTL after Refresh() = %v, want %v", got, 10*time.Second)
+	}
+}
+
+func TestLockAutoExtend(t *testing.T) {
+	locker, server := newTestLocker(t)
+	ctx := context.Background()
+
+	ttl := 300 * time.Millisecond
+	lock, err := locker.TryObtain(ctx, "orders", ttl)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+
+	// Use up most of the lease; the keep-alive renews it every ttl/3
+	server.FastForward(250 * time.Millisecond)
+	time.Sleep(ttl / 2)
+
+	if got := server.TTL("{orders}"); got <= 50*time.Millisecond {
+		t.Fatalf("lock TTL = %v, want it renewed to about %v", got, ttl)
+	}
+	select {
+	case <-lock.Lost():
+		t.Fatal("Lost() closed while the lock is held")
+	default:
+	}
+
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+	if server.Exists("{orders}") {
+		t.Error("lock key still exists after Release()")
+	}
+}
+
+func TestLockAutoExtendDetectsLoss(t *testing.T) {
+	locker, server := newTestLocker(t)
+
+	lock, err := locker.TryObtain(context.Background(), "orders", 150*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+
+	server.Del("{orders}")
+	select {
+	case <-lock.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() not closed after the lock key was removed")
+	}
+}
+
+func TestLockObtainContextCancelled(t *testing.T) {
+	locker, _ := newTestLocker(t, WithAutoExtend(false), WithRetryInterval(10*time.Millisecond))
+
+	held, err := locker.TryObtain(context.Background(), "orders", time.Minute)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+	defer held.Release(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := locker.Obtain(ctx, "orders", time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Obtain() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestLockFencingTokensIncrease(t *testing.T) {
+	locker, server := newTestLocker(t, WithAutoExtend(false), WithFenceTTL(time.Hour))
+	ctx := context.Background()
+
+	var previous int64
+	obtain := func() {
+		t.Helper()
+		lock, err := locker.TryObtain(ctx, "orders", time.Second)
+		if err != nil {
+			t.Fatalf("TryObtain() error = %v", err)
+		}
+		if lock.FencingToken() <= previous {
+			t.Fatalf("FencingToken() = %d, want more than %d", lock.FencingToken(), previous)
+		}
+		previous = lock.FencingToken()
+		if err := lock.Release(ctx); err != nil {
+			t.Fatalf("Release() error = %v", err)
+		}
+	}
+
+	obtain()
+	if since := time.Now().Add(-time.Minute).UnixMicro(); previous < since {
+		t.Fatalf("FencingToken() = %d, want it seeded from the server time %d", previous, since)
+	}
+	for i := 0; i < 4; i++ {
+		obtain()
+	}
+	if got := server.TTL("{orders}:fence"); got != time.Hour {
+		t.Errorf("fence counter TTL = %v, want %v", got, time.Hour)
+	}
+
+	// Tokens keep increasing once the counter has expired
+	server.FastForward(2 * time.Hour)
+	if server.Exists("{orders}:fence") {
+		t.Fatal("fence counter did not expire")
+	}
+	obtain()
+}
+
+func TestLockWithoutFencing(t *testing.T) {
+	locker, server := newTestLocker(t, WithAutoExtend(false), WithFencing(false))
+	ctx := context.Background()
+
+	lock, err := locker.TryObtain(ctx, "orders", time.Second)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+	if lock.FencingToken() != 0 {
+		t.Errorf("FencingToken() = %d, want 0", lock.FencingToken())
+	}
+	if server.Exists("{orders}:fence") {
+		t.Error("fence counter created with fencing disabled")
+	}
+
+	if _, err := locker.TryObtain(ctx, "orders", time.Second); !errors.Is(err, ErrLockNotObtained) {
+		t.Fatalf("TryObtain() on held lock error = %v, want ErrLockNotObtained", err)
+	}
+	if err := lock.Release(ctx); err != nil {
+		t.Fatalf("Release() error = %v", err)
+	}
+}
+
+func TestLockAutoExtendDetectsUnreachableServer(t *testing.T) {
+	locker, server := newTestLocker(t)
+
+	lock, err := locker.TryObtain(context.Background(), "orders", 150*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TryObtain() error = %v", err)
+	}
+
+	// Refreshes now fail with transport errors until the lease has run out
+	server.Close()
+	select {
+	case <-lock.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() not closed after the lease ran out without a refresh")
+	}
+}
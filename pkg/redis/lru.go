@@ -0,0 +1,113 @@
+package redis
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// lruCache is a bounded in-memory cache with per-entry expiry
+type lruCache[V any] struct {
+	capacity int
+	ttl      time.Duration
+	entries  *list.List
+	items    map[string]*list.Element
+	mu       sync.Mutex
+}
+
+// lruEntry is an element of the recency list
+type lruEntry[V any] struct {
+	key    string
+	value  V
+	expiry time.Time
+}
+
+// newLRUCache creates an empty cache holding at most capacity entries for ttl
+// each. A capacity below 1 is raised to 1.
+func newLRUCache[V any](capacity int, ttl time.Duration) *lruCache[V] {
+	return &lruCache[V]{
+		capacity: max(capacity, 1),
+		ttl:      ttl,
+		entries:  list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+// get returns an unexpired value and marks it as recently used
+func (c *lruCache[V]) get(key string) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.items[key]
+	if !exists {
+		var zero V
+		return zero, false
+	}
+
+	entry := element.Value.(*lruEntry[V])
+	if time.Now().After(entry.expiry) {
+		c.remove(element)
+		var zero V
+		return zero, false
+	}
+
+	c.entries.MoveToFront(element)
+	return entry.value, true
+}
+
+// set stores a value and returns how many entries were evicted to make room
+func (c *lruCache[V]) set(key string, value V) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiry := time.Now().Add(c.ttl)
+	if element, exists := c.items[key]; exists {
+		entry := element.Value.(*lruEntry[V])
+		entry.value = value
+		entry.expiry = expiry
+		c.entries.MoveToFront(element)
+		return 0
+	}
+
+	c.items[key] = c.entries.PushFront(&lruEntry[V]{key: key, value: value, expiry: expiry})
+
+	evicted := 0
+	for c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+		evicted++
+	}
+	return evicted
+}
+
+// delete removes a key
+func (c *lruCache[V]) delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.items[key]; exists {
+		c.remove(element)
+	}
+}
+
+// clear removes every entry
+func (c *lruCache[V]) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries.Init()
+	c.items = make(map[string]*list.Element)
+}
+
+// len returns the number of entries, including expired ones not yet removed
+func (c *lruCache[V]) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.entries.Len()
+}
+
+// remove unlinks an element; the caller holds the lock
+func (c *lruCache[V]) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.items, element.Value.(*lruEntry[V]).key)
+}
@@ -2,8 +2,9 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -12,26 +13,50 @@ import (
 
 // Manager handles Redis operations
 type Manager struct {
-	client *redis.Client
+	client redis.UniversalClient
 	logger *zap.Logger
+	codec  Codec
+}
+
+// ManagerOption represents a function that configures the Manager
+type ManagerOption func(*Manager)
+
+// WithCodec sets the codec used to serialize values
+func WithCodec(codec Codec) ManagerOption {
+	return func(m *Manager) {
+		m.codec = codec
+	}
 }
 
 // NewManager creates a new Redis manager
-func NewManager(client *redis.Client, logger *zap.Logger) *Manager {
-	return &Manager{
+func NewManager(client redis.UniversalClient, logger *zap.Logger, opts ...ManagerOption) *Manager {
+	manager := &Manager{
 		client: client,
 		logger: logger,
+		codec:  DefaultCodec,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(manager)
 	}
+
+	return manager
+}
+
+// Client returns the underlying Redis client
+func (m *Manager) Client() redis.UniversalClient {
+	return m.client
 }
 
 // Set sets a key-value pair in Redis with optional expiration
 func (m *Manager) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
-	jsonValue, err := json.Marshal(value)
+	data, err := m.codec.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	err = m.client.Set(ctx, key, jsonValue, expiration).Err()
+	err = m.client.Set(ctx, key, data, expiration).Err()
 	if err != nil {
 		return fmt.Errorf("failed to set key %s: %w", key, err)
 	}
@@ -39,19 +64,20 @@ func (m *Manager) Set(ctx context.Context, key string, value any, expiration tim
 	return nil
 }
 
-// Get gets a value from Redis by key and unmarshals it into the provided value
+// Get gets a value from Redis by key and unmarshals it into the provided value.
+// It returns ErrNotFound if the key is missing and ErrDecode if it cannot be unmarshalled.
 func (m *Manager) Get(ctx context.Context, key string, value any) error {
-	jsonValue, err := m.client.Get(ctx, key).Bytes()
+	data, err := m.client.Get(ctx, key).Bytes()
 	if err != nil {
-		if err == redis.Nil {
-			return fmt.Errorf("key %s does not exist", key)
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("key %s: %w", key, ErrNotFound)
 		}
 		return fmt.Errorf("failed to get key %s: %w", key, err)
 	}
 
-	err = json.Unmarshal(jsonValue, value)
+	err = m.codec.Unmarshal(data, value)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal value for key %s: %w", key, err)
+		return fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
 	}
 
 	return nil
@@ -106,3 +132,33 @@ func (m *Manager) GetTimeToLive(ctx context.Context, key string) (time.Duration,
 func (m *Manager) Close() error {
 	return m.client.Close()
 }
+
+// encodeAll marshals values with the manager's codec
+func (m *Manager) encodeAll(values []any) ([]any, error) {
+	encoded := make([]any, len(values))
+	for i, value := range values {
+		data, err := m.codec.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal value: %w", err)
+		}
+		encoded[i] = data
+	}
+	return encoded, nil
+}
+
+// decodeAll unmarshals raw values into dest, which must be a pointer to a slice
+func (m *Manager) decodeAll(key string, values []string, dest any) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Pointer || target.IsNil() || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("destination for key %s must be a pointer to a slice, got %T", key, dest)
+	}
+
+	slice := reflect.MakeSlice(target.Elem().Type(), len(values), len(values))
+	for i, value := range values {
+		if err := m.codec.Unmarshal([]byte(value), slice.Index(i).Addr().Interface()); err != nil {
+			return fmt.Errorf("failed to unmarshal value %d for key %s: %w: %w", i, key, ErrDecode, err)
+		}
+	}
+	target.Elem().Set(slice)
+	return nil
+}
@@ -0,0 +1,263 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultNearCacheSize is the default number of entries kept in memory
+	DefaultNearCacheSize = 10000
+
+	// DefaultNearCacheTTL is the default lifetime of an in-memory entry. It
+	// should be shorter than the Redis TTL and bounds staleness if an
+	// invalidation message is missed.
+	DefaultNearCacheTTL = 30 * time.Second
+
+	// nearCacheChannelPrefix prefixes the invalidation channel of a store namespace
+	nearCacheChannelPrefix = "nearcache:"
+)
+
+// NearCacheOption represents a function that configures a NearCache
+type NearCacheOption func(*nearCacheOptions)
+
+// nearCacheOptions holds the settings shared by near caches of every type
+type nearCacheOptions struct {
+	size int
+	ttl  time.Duration
+}
+
+// WithNearCacheSize sets the maximum number of entries kept in memory, which
+// must be at least 1
+func WithNearCacheSize(size int) NearCacheOption {
+	return func(o *nearCacheOptions) {
+		o.size = size
+	}
+}
+
+// WithNearCacheTTL sets the lifetime of in-memory entries
+func WithNearCacheTTL(ttl time.Duration) NearCacheOption {
+	return func(o *nearCacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// NearCacheStats holds in-memory cache statistics since creation
+type NearCacheStats struct {
+	Hits      int64
+	Misses    int64
+	Evictions int64
+	Size      int
+}
+
+// invalidation is published when entries change so that other instances drop them
+type invalidation struct {
+	Source string   `json:"s"`
+	IDs    []string `json:"k,omitempty"`
+	All    bool     `json:"a,omitempty"`
+}
+
+// NearCache keeps recently read values of a Store in process memory. Writes
+// through the near cache are announced over pub/sub so that every instance
+// drops its copy. Cached values are not copied: every caller on an instance
+// gets the same value, so pointers, maps and slices in it must not be modified.
+type NearCache[T any] struct {
+	store        *Store[T]
+	local        *lruCache[T]
+	topic        *Topic[invalidation]
+	subscription *Subscription
+	instanceID   string
+	generation   atomic.Uint64
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	evictions atomic.Int64
+	requests  metric.Int64Counter
+	evicted   metric.Int64Counter
+	hitAttrs  metric.MeasurementOption
+	missAttrs metric.MeasurementOption
+	attrs     metric.MeasurementOption
+}
+
+// NewNearCache creates a near cache in front of store and subscribes to invalidations
+func NewNearCache[T any](ctx context.Context, store *Store[T], opts ...NearCacheOption) (*NearCache[T], error) {
+	options := &nearCacheOptions{
+		size: DefaultNearCacheSize,
+		ttl:  DefaultNearCacheTTL,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.size < 1 {
+		return nil, fmt.Errorf("near cache size must be at least 1, got %d", options.size)
+	}
+
+	instanceID, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	logger := store.manager.logger
+	meter := otel.Meter(meterName)
+	requests, err := meter.Int64Counter("redis.nearcache.requests",
+		metric.WithDescription("Near cache lookups by result"))
+	if err != nil {
+		logger.Warn("Failed to create near cache request counter", zap.Error(err))
+	}
+	evicted, err := meter.Int64Counter("redis.nearcache.evictions",
+		metric.WithDescription("Near cache entries evicted to stay within size"))
+	if err != nil {
+		logger.Warn("Failed to create near cache eviction counter", zap.Error(err))
+	}
+
+	cache := &NearCache[T]{
+		store:      store,
+		local:      newLRUCache[T](options.size, options.ttl),
+		instanceID: instanceID,
+		requests:   requests,
+		evicted:    evicted,
+		hitAttrs: metric.WithAttributes(attribute.String("cache", store.namespace),
+			attribute.String("result", "hit")),
+		missAttrs: metric.WithAttributes(attribute.String("cache", store.namespace),
+			attribute.String("result", "miss")),
+		attrs: metric.WithAttributes(attribute.String("cache", store.namespace)),
+		// Invalidations are always JSON so they do not depend on the manager's codec
+		topic: &Topic[invalidation]{
+			manager: store.manager,
+			channel: nearCacheChannelPrefix + store.namespace,
+			codec:   DefaultCodec,
+		},
+	}
+
+	cache.subscription, err = cache.topic.Subscribe(ctx, cache.applyInvalidation)
+	if err != nil {
+		return nil, err
+	}
+	return cache, nil
+}
+
+// Get returns the value for id from memory, or from Redis on a miss. The
+// value is shared with other callers and must not be modified.
+func (n *NearCache[T]) Get(ctx context.Context, id string) (T, error) {
+	if value, ok := n.local.get(id); ok {
+		n.record(&n.hits, n.hitAttrs)
+		return value, nil
+	}
+	n.record(&n.misses, n.missAttrs)
+
+	// An invalidation while reading means the value may already be stale
+	generation := n.generation.Load()
+	value, err := n.store.Get(ctx, id)
+	if err != nil {
+		return value, err
+	}
+	if n.generation.Load() == generation {
+		if evicted := n.local.set(id, value); evicted > 0 {
+			n.evictions.Add(int64(evicted))
+			if n.evicted != nil {
+				n.evicted.Add(ctx, int64(evicted), n.attrs)
+			}
+		}
+	}
+	return value, nil
+}
+
+// Set stores the value in Redis and invalidates it on every instance
+func (n *NearCache[T]) Set(ctx context.Context, id string, value T, expiration time.Duration) error {
+	if err := n.store.Set(ctx, id, value, expiration); err != nil {
+		return err
+	}
+	return n.Invalidate(ctx, id)
+}
+
+// Delete removes the values from Redis and invalidates them on every instance
+func (n *NearCache[T]) Delete(ctx context.Context, ids ...string) (int64, error) {
+	deleted, err := n.store.Delete(ctx, ids...)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, n.Invalidate(ctx, ids...)
+}
+
+// Invalidate drops ids from memory on every instance. Use it when the values
+// were changed in Redis by other means.
+func (n *NearCache[T]) Invalidate(ctx context.Context, ids ...string) error {
+	message := invalidation{Source: n.instanceID, IDs: ids}
+	n.applyLocal(message)
+	return n.publish(ctx, message)
+}
+
+// InvalidateAll drops every entry from memory on every instance
+func (n *NearCache[T]) InvalidateAll(ctx context.Context) error {
+	message := invalidation{Source: n.instanceID, All: true}
+	n.applyLocal(message)
+	return n.publish(ctx, message)
+}
+
+// Stats returns the in-memory hit, miss and eviction counts
+func (n *NearCache[T]) Stats() NearCacheStats {
+	return NearCacheStats{
+		Hits:      n.hits.Load(),
+		Misses:    n.misses.Load(),
+		Evictions: n.evictions.Load(),
+		Size:      n.local.len(),
+	}
+}
+
+// Close stops listening for invalidations and clears the in-memory entries
+func (n *NearCache[T]) Close() error {
+	err := n.subscription.Close()
+	n.local.clear()
+	return err
+}
+
+// publish announces an invalidation to the other instances
+func (n *NearCache[T]) publish(ctx context.Context, message invalidation) error {
+	if _, err := n.topic.Publish(ctx, message); err != nil {
+		return fmt.Errorf("failed to publish near cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// applyInvalidation handles invalidations published by other instances
+func (n *NearCache[T]) applyInvalidation(_ context.Context, message invalidation) error {
+	if message.Source == n.instanceID {
+		return nil
+	}
+	if !message.All && len(message.IDs) == 0 {
+		return errors.New("empty near cache invalidation")
+	}
+	n.applyLocal(message)
+	return nil
+}
+
+// applyLocal drops the invalidated entries from memory
+func (n *NearCache[T]) applyLocal(message invalidation) {
+	n.generation.Add(1)
+	if message.All {
+		n.local.clear()
+		return
+	}
+	for _, id := range message.IDs {
+		n.local.delete(id)
+	}
+}
+
+// record counts a lookup result
+func (n *NearCache[T]) record(counter *atomic.Int64, attrs metric.MeasurementOption) {
+	counter.Add(1)
+	if n.requests != nil {
+		n.requests.Add(context.Background(), 1, attrs)
+	}
+}
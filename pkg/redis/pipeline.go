@@ -0,0 +1,192 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Result is the typed outcome of a command queued on a Pipeline. It can be
+// read once the pipeline has been executed.
+type Result[T any] struct {
+	value T
+	err   error
+}
+
+// Value returns the command's value or error
+func (r *Result[T]) Value() (T, error) {
+	return r.value, r.err
+}
+
+// Err returns the command's error
+func (r *Result[T]) Err() error {
+	return r.err
+}
+
+// Pipeline queues commands and sends them to Redis in a single round trip.
+// Values are encoded with the manager's codec and results are decoded when
+// the pipeline is executed.
+type Pipeline struct {
+	manager   *Manager
+	pipe      redis.Pipeliner
+	resolvers []func(aborted error)
+	err       error
+}
+
+// Pipeline creates a pipeline. Its commands are not atomic; use TxPipeline
+// or Transaction for that.
+func (m *Manager) Pipeline() *Pipeline {
+	return m.newPipeline(m.client.Pipeline())
+}
+
+// TxPipeline creates a pipeline whose commands run atomically in MULTI/EXEC
+func (m *Manager) TxPipeline() *Pipeline {
+	return m.newPipeline(m.client.TxPipeline())
+}
+
+// newPipeline wraps a go-redis pipeline
+func (m *Manager) newPipeline(pipe redis.Pipeliner) *Pipeline {
+	return &Pipeline{
+		manager: m,
+		pipe:    pipe,
+	}
+}
+
+// Commands returns the underlying pipeline to queue commands without a typed
+// helper. Their go-redis results are filled in by Exec.
+func (p *Pipeline) Commands() redis.Pipeliner {
+	return p.pipe
+}
+
+// Len returns the number of queued commands
+func (p *Pipeline) Len() int {
+	return p.pipe.Len()
+}
+
+// Set queues a SET of an encoded value with optional expiration
+func (p *Pipeline) Set(ctx context.Context, key string, value any, expiration time.Duration) *Result[bool] {
+	data, err := p.manager.codec.Marshal(value)
+	if err != nil {
+		err = fmt.Errorf("failed to marshal value for key %s: %w", key, err)
+		p.err = errors.Join(p.err, err)
+		return &Result[bool]{err: err}
+	}
+
+	cmd := p.pipe.Set(ctx, key, data, expiration)
+	return queue(p, func() (bool, error) {
+		if err := cmd.Err(); err != nil {
+			return false, fmt.Errorf("failed to set key %s: %w", key, err)
+		}
+		return true, nil
+	})
+}
+
+// Delete queues a DEL of a key
+func (p *Pipeline) Delete(ctx context.Context, key string) *Result[bool] {
+	cmd := p.pipe.Del(ctx, key)
+	return queue(p, func() (bool, error) {
+		if err := cmd.Err(); err != nil {
+			return false, fmt.Errorf("failed to delete key %s: %w", key, err)
+		}
+		return cmd.Val() > 0, nil
+	})
+}
+
+// Increment queues an INCR of a counter
+func (p *Pipeline) Increment(ctx context.Context, key string) *Result[int64] {
+	cmd := p.pipe.Incr(ctx, key)
+	return queue(p, func() (int64, error) {
+		if err := cmd.Err(); err != nil {
+			return 0, fmt.Errorf("failed to increment key %s: %w", key, err)
+		}
+		return cmd.Val(), nil
+	})
+}
+
+// Exists queues an existence check of a key
+func (p *Pipeline) Exists(ctx context.Context, key string) *Result[bool] {
+	cmd := p.pipe.Exists(ctx, key)
+	return queue(p, func() (bool, error) {
+		if err := cmd.Err(); err != nil {
+			return false, fmt.Errorf("failed to check existence of key %s: %w", key, err)
+		}
+		return cmd.Val() > 0, nil
+	})
+}
+
+// Expire queues setting the expiration time of a key
+func (p *Pipeline) Expire(ctx context.Context, key string, expiration time.Duration) *Result[bool] {
+	cmd := p.pipe.Expire(ctx, key, expiration)
+	return queue(p, func() (bool, error) {
+		if err := cmd.Err(); err != nil {
+			return false, fmt.Errorf("failed to set expiration for key %s: %w", key, err)
+		}
+		return cmd.Val(), nil
+	})
+}
+
+// PipelineGet queues a GET whose value is decoded into T. The result's error
+// is ErrNotFound if the key is missing and ErrDecode if it cannot be unmarshalled.
+func PipelineGet[T any](ctx context.Context, p *Pipeline, key string) *Result[T] {
+	cmd := p.pipe.Get(ctx, key)
+	return queue(p, func() (T, error) {
+		var value T
+		data, err := cmd.Bytes()
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				return value, fmt.Errorf("key %s: %w", key, ErrNotFound)
+			}
+			return value, fmt.Errorf("failed to get key %s: %w", key, err)
+		}
+		if err := p.manager.codec.Unmarshal(data, &value); err != nil {
+			return value, fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
+		}
+		return value, nil
+	})
+}
+
+// Exec sends the queued commands and fills in their results. It returns an
+// error if the pipeline could not be sent or a command failed; a missing key
+// is only reported by its result. Nothing is sent if a value could not be
+// marshalled. The pipeline can be reused afterwards.
+func (p *Pipeline) Exec(ctx context.Context) error {
+	resolvers, queueErr := p.resolvers, p.err
+	p.resolvers, p.err = nil, nil
+	if queueErr != nil {
+		p.pipe.Discard()
+		err := fmt.Errorf("failed to execute pipeline: %w", queueErr)
+		for _, resolve := range resolvers {
+			resolve(err)
+		}
+		return err
+	}
+
+	cmds, err := p.pipe.Exec(ctx)
+	for _, resolve := range resolvers {
+		resolve(nil)
+	}
+
+	if err == nil || errors.Is(err, redis.Nil) {
+		err = firstCmdError(cmds)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to execute pipeline: %w", err)
+	}
+	return nil
+}
+
+// queue registers a result to be filled in when the pipeline is executed
+func queue[T any](p *Pipeline, resolve func() (T, error)) *Result[T] {
+	result := &Result[T]{err: ErrNotExecuted}
+	p.resolvers = append(p.resolvers, func(aborted error) {
+		if aborted != nil {
+			result.err = aborted
+			return
+		}
+		result.value, result.err = resolve()
+	})
+	return result
+}
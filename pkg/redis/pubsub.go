@@ -0,0 +1,94 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// Topic publishes and subscribes to typed messages on a pub/sub channel.
+// Messages are fire-and-forget: subscribers that are not connected miss them.
+type Topic[T any] struct {
+	manager *Manager
+	channel string
+	codec   Codec
+}
+
+// NewTopic creates a new typed pub/sub topic using the manager's codec
+func NewTopic[T any](manager *Manager, channel string) *Topic[T] {
+	return &Topic[T]{
+		manager: manager,
+		channel: channel,
+		codec:   manager.codec,
+	}
+}
+
+// Publish sends a message and returns the number of subscribers that received it
+func (t *Topic[T]) Publish(ctx context.Context, value T) (int64, error) {
+	data, err := t.codec.Marshal(value)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal message for channel %s: %w", t.channel, err)
+	}
+
+	receivers, err := t.manager.client.Publish(ctx, t.channel, data).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to publish to channel %s: %w", t.channel, err)
+	}
+	return receivers, nil
+}
+
+// Subscription is an active pub/sub subscription
+type Subscription struct {
+	pubsub *redis.PubSub
+	done   chan struct{}
+	once   sync.Once
+}
+
+// Subscribe calls handler for every message on the topic until the
+// subscription is closed. Messages are handled one at a time in order;
+// handler errors and undecodable messages are logged and skipped.
+func (t *Topic[T]) Subscribe(ctx context.Context, handler func(context.Context, T) error) (*Subscription, error) {
+	pubsub := t.manager.client.Subscribe(ctx, t.channel)
+
+	// Wait for the subscription to be confirmed so no message published afterwards is missed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to channel %s: %w", t.channel, err)
+	}
+
+	subscription := &Subscription{
+		pubsub: pubsub,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(subscription.done)
+		for message := range pubsub.Channel() {
+			var value T
+			if err := t.codec.Unmarshal([]byte(message.Payload), &value); err != nil {
+				t.manager.logger.Warn("Failed to unmarshal message",
+					zap.String("channel", message.Channel), zap.Error(err))
+				continue
+			}
+			if err := handler(ctx, value); err != nil {
+				t.manager.logger.Warn("Message handler failed",
+					zap.String("channel", message.Channel), zap.Error(err))
+			}
+		}
+	}()
+
+	return subscription, nil
+}
+
+// Close unsubscribes and waits for the message being handled to finish
+func (s *Subscription) Close() error {
+	var err error
+	s.once.Do(func() {
+		err = s.pubsub.Close()
+		<-s.done
+	})
+	return err
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Enqueue appends values to the tail of a list. When maxLen is positive the
+// oldest entries are dropped so that the list keeps at most maxLen values.
+// It returns the length of the list before trimming.
+func (m *Manager) Enqueue(ctx context.Context, key string, maxLen int64, values ...any) (int64, error) {
+	encoded, err := m.encodeAll(values)
+	if err != nil {
+		return 0, err
+	}
+
+	var length *redis.IntCmd
+	_, err = m.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		length = pipe.RPush(ctx, key, encoded...)
+		if maxLen > 0 {
+			pipe.LTrim(ctx, key, -maxLen, -1)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to enqueue to list %s: %w", key, err)
+	}
+	return length.Val(), nil
+}
+
+// Dequeue removes the value at the head of a list and unmarshals it into value.
+// It returns ErrNotFound if the list is empty.
+func (m *Manager) Dequeue(ctx context.Context, key string, value any) error {
+	data, err := m.client.LPop(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("list %s: %w", key, ErrNotFound)
+		}
+		return fmt.Errorf("failed to dequeue from list %s: %w", key, err)
+	}
+	return m.decodeQueued(key, data, value)
+}
+
+// DequeueWait is like Dequeue but waits up to timeout for a value to arrive.
+// A zero timeout waits until ctx is done.
+func (m *Manager) DequeueWait(ctx context.Context, key string, timeout time.Duration, value any) error {
+	result, err := m.client.BLPop(ctx, timeout, key).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("list %s: %w", key, ErrNotFound)
+		}
+		return fmt.Errorf("failed to dequeue from list %s: %w", key, err)
+	}
+	// BLPOP replies with the key followed by the value
+	return m.decodeQueued(key, []byte(result[1]), value)
+}
+
+// QueueRange decodes the values between start and stop, inclusive and
+// zero-based from the head, into values, which must be a pointer to a slice.
+// Negative indexes count from the tail.
+func (m *Manager) QueueRange(ctx context.Context, key string, start, stop int64, values any) error {
+	result, err := m.client.LRange(ctx, key, start, stop).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get range of list %s: %w", key, err)
+	}
+	return m.decodeAll(key, result, values)
+}
+
+// QueueLength returns the number of values in a list
+func (m *Manager) QueueLength(ctx context.Context, key string) (int64, error) {
+	result, err := m.client.LLen(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get length of list %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// decodeQueued unmarshals a value popped from a list
+func (m *Manager) decodeQueued(key string, data []byte, value any) error {
+	if err := m.codec.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to unmarshal value from list %s: %w: %w", key, ErrDecode, err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultScanCount is the default number of keys requested per SCAN call
+const DefaultScanCount = 100
+
+// ErrStopScan can be returned by a Scan callback to stop iterating without an error
+var ErrStopScan = errors.New("stop scan")
+
+// Scan calls fn for every key matching a glob-style pattern, using SCAN so
+// that Redis is not blocked. On a cluster every master is scanned. Keys
+// changed during the scan may be missed or visited twice, so fn should be
+// idempotent. fn is never called concurrently.
+func (m *Manager) Scan(ctx context.Context, pattern string, count int64, fn func(key string) error) error {
+	if count <= 0 {
+		count = DefaultScanCount
+	}
+
+	var err error
+	if cluster, ok := m.client.(*redis.ClusterClient); ok {
+		var (
+			mu      sync.Mutex
+			stopErr error
+		)
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return scanNode(ctx, node, pattern, count, func(key string) error {
+				mu.Lock()
+				defer mu.Unlock()
+				// Masters are scanned in parallel; stop all of them once fn fails
+				if stopErr == nil {
+					stopErr = fn(key)
+				}
+				return stopErr
+			})
+		})
+	} else {
+		err = scanNode(ctx, m.client, pattern, count, fn)
+	}
+
+	if errors.Is(err, ErrStopScan) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to scan keys matching %s: %w", pattern, err)
+	}
+	return nil
+}
+
+// ScanKeys returns every key matching a glob-style pattern
+func (m *Manager) ScanKeys(ctx context.Context, pattern string, count int64) ([]string, error) {
+	var keys []string
+	err := m.Scan(ctx, pattern, count, func(key string) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// scanNode iterates the keys of a single node
+func scanNode(ctx context.Context, client redis.Cmdable, pattern string, count int64, fn func(key string) error) error {
+	iter := client.Scan(ctx, 0, pattern, count).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,88 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Scripts is a registry of named Lua scripts. Scripts are called by their
+// SHA1 with EVALSHA and sent in full with EVAL only when Redis does not have
+// them cached, e.g. after a restart or failover.
+type Scripts struct {
+	manager *Manager
+	mu      sync.RWMutex
+	scripts map[string]*redis.Script
+}
+
+// NewScripts creates an empty script registry
+func NewScripts(manager *Manager) *Scripts {
+	return &Scripts{
+		manager: manager,
+		scripts: make(map[string]*redis.Script),
+	}
+}
+
+// Register adds a script under a name, replacing any script registered
+// under the same name
+func (s *Scripts) Register(name, source string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[name] = redis.NewScript(source)
+}
+
+// Load caches every registered script in Redis, on every node of a cluster,
+// so that the first calls do not need to send the script body. Calling it
+// is optional.
+func (s *Scripts) Load(ctx context.Context) error {
+	s.mu.RLock()
+	scripts := maps.Clone(s.scripts)
+	s.mu.RUnlock()
+
+	names := make([]string, 0, len(scripts))
+	for name := range scripts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if err := scripts[name].Load(ctx, s.manager.client).Err(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to load script %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run calls a registered script. The result is read from the returned
+// command, e.g. with Int64 or StringSlice; a nil reply is not an error.
+// It returns ErrUnknownScript if no script is registered under name.
+func (s *Scripts) Run(ctx context.Context, name string, keys []string, args ...any) (*redis.Cmd, error) {
+	script, err := s.script(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := script.Run(ctx, s.manager.client, keys, args...)
+	if err := cmd.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return cmd, fmt.Errorf("failed to run script %s: %w", name, err)
+	}
+	return cmd, nil
+}
+
+// script returns a registered script by name
+func (s *Scripts) script(name string) (*redis.Script, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	script, ok := s.scripts[name]
+	if !ok {
+		return nil, fmt.Errorf("script %s: %w", name, ErrUnknownScript)
+	}
+	return script, nil
+}
@@ -0,0 +1,67 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+)
+
+// SAdd adds members to a set and returns how many were added
+func (m *Manager) SAdd(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := m.encodeAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := m.client.SAdd(ctx, key, encoded...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add to set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// SRemove removes members from a set and returns how many existed
+func (m *Manager) SRemove(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := m.encodeAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := m.client.SRem(ctx, key, encoded...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove from set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// SIsMember checks if a value is a member of a set
+func (m *Manager) SIsMember(ctx context.Context, key string, member any) (bool, error) {
+	data, err := m.codec.Marshal(member)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal member of set %s: %w", key, err)
+	}
+
+	result, err := m.client.SIsMember(ctx, key, data).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check membership of set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// SMembers decodes every member of a set into members, which must be a
+// pointer to a slice. The order is unspecified.
+func (m *Manager) SMembers(ctx context.Context, key string, members any) error {
+	values, err := m.client.SMembers(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to get members of set %s: %w", key, err)
+	}
+	return m.decodeAll(key, values, members)
+}
+
+// SCard returns the number of members of a set
+func (m *Manager) SCard(ctx context.Context, key string) (int64, error) {
+	result, err := m.client.SCard(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count set %s: %w", key, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,159 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Sorted set, set and queue members are encoded with the manager's codec and
+// compared by their encoding, so a member must be encoded the same way to be
+// found again.
+
+// ScoredMember is a sorted set member with its score
+type ScoredMember struct {
+	Member any
+	Score  float64
+}
+
+// ScoreRange selects sorted set members by score. Min and Max use Redis
+// notation, e.g. "-inf", "+inf" or "(100" for an exclusive bound. A Count of
+// zero returns every member in range.
+type ScoreRange struct {
+	Min     string
+	Max     string
+	Offset  int64
+	Count   int64
+	Reverse bool
+}
+
+// ZAdd adds members to a sorted set, updating the score of existing ones,
+// and returns how many were added
+func (m *Manager) ZAdd(ctx context.Context, key string, members ...ScoredMember) (int64, error) {
+	entries := make([]redis.Z, len(members))
+	for i, member := range members {
+		data, err := m.codec.Marshal(member.Member)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal member of sorted set %s: %w", key, err)
+		}
+		entries[i] = redis.Z{Score: member.Score, Member: data}
+	}
+
+	result, err := m.client.ZAdd(ctx, key, entries...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to add to sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZIncrBy increments the score of a member and returns the new score
+func (m *Manager) ZIncrBy(ctx context.Context, key string, increment float64, member any) (float64, error) {
+	data, err := m.codec.Marshal(member)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal member of sorted set %s: %w", key, err)
+	}
+
+	result, err := m.client.ZIncrBy(ctx, key, increment, string(data)).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment score in sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZRemove removes members from a sorted set and returns how many existed
+func (m *Manager) ZRemove(ctx context.Context, key string, members ...any) (int64, error) {
+	encoded, err := m.encodeAll(members)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := m.client.ZRem(ctx, key, encoded...).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove from sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZScore returns the score of a member.
+// It returns ErrNotFound if the member is not in the set.
+func (m *Manager) ZScore(ctx context.Context, key string, member any) (float64, error) {
+	data, err := m.codec.Marshal(member)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal member of sorted set %s: %w", key, err)
+	}
+
+	result, err := m.client.ZScore(ctx, key, string(data)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, fmt.Errorf("member of sorted set %s: %w", key, ErrNotFound)
+		}
+		return 0, fmt.Errorf("failed to get score in sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZRank returns the zero-based rank of a member, counting from the highest
+// score when reverse is set. It returns ErrNotFound if the member is not in the set.
+func (m *Manager) ZRank(ctx context.Context, key string, member any, reverse bool) (int64, error) {
+	data, err := m.codec.Marshal(member)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal member of sorted set %s: %w", key, err)
+	}
+
+	cmd := m.client.ZRank
+	if reverse {
+		cmd = m.client.ZRevRank
+	}
+	result, err := cmd(ctx, key, string(data)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, fmt.Errorf("member of sorted set %s: %w", key, ErrNotFound)
+		}
+		return 0, fmt.Errorf("failed to get rank in sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZCard returns the number of members of a sorted set
+func (m *Manager) ZCard(ctx context.Context, key string) (int64, error) {
+	result, err := m.client.ZCard(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count sorted set %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// ZRangeByScore decodes the members within a score range into members, which
+// must be a pointer to a slice, and returns their scores in the same order
+func (m *Manager) ZRangeByScore(ctx context.Context, key string, scoreRange ScoreRange, members any) ([]float64, error) {
+	args := redis.ZRangeArgs{
+		Key:     key,
+		Start:   scoreRange.Min,
+		Stop:    scoreRange.Max,
+		ByScore: true,
+		Rev:     scoreRange.Reverse,
+		Offset:  scoreRange.Offset,
+		Count:   scoreRange.Count,
+	}
+	if args.Count == 0 && args.Offset != 0 {
+		args.Count = -1
+	}
+
+	entries, err := m.client.ZRangeArgsWithScores(ctx, args).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get range of sorted set %s: %w", key, err)
+	}
+
+	values := make([]string, len(entries))
+	scores := make([]float64, len(entries))
+	for i, entry := range entries {
+		values[i], _ = entry.Member.(string)
+		scores[i] = entry.Score
+	}
+	if err := m.decodeAll(key, values, members); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
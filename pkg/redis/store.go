@@ -0,0 +1,220 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// keySeparator joins the parts of a key
+const keySeparator = ":"
+
+// Namespace builds a key prefix such as "market-data:quote" from a service
+// name and an entity name
+func Namespace(parts ...string) string {
+	return strings.Join(parts, keySeparator)
+}
+
+// Store is a typed repository of values of type T kept under a common key prefix
+type Store[T any] struct {
+	manager   *Manager
+	namespace string
+	codec     Codec
+}
+
+// StoreOption represents a function that configures a Store
+type StoreOption func(*storeOptions)
+
+// storeOptions holds the settings shared by stores of every type
+type storeOptions struct {
+	codec Codec
+}
+
+// WithStoreCodec sets the codec of a store, overriding the manager's codec
+func WithStoreCodec(codec Codec) StoreOption {
+	return func(o *storeOptions) {
+		o.codec = codec
+	}
+}
+
+// NewStore creates a new typed store whose keys are "<namespace>:<id>"
+func NewStore[T any](manager *Manager, namespace string, opts ...StoreOption) *Store[T] {
+	options := &storeOptions{codec: manager.codec}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Store[T]{
+		manager:   manager,
+		namespace: namespace,
+		codec:     options.codec,
+	}
+}
+
+// Key returns the Redis key of an id
+func (s *Store[T]) Key(id string) string {
+	return s.namespace + keySeparator + id
+}
+
+// Get returns the value stored for id. It returns ErrNotFound if the key is
+// missing and ErrDecode if the stored value cannot be decoded.
+func (s *Store[T]) Get(ctx context.Context, id string) (T, error) {
+	var zero T
+	key := s.Key(id)
+	data, err := s.manager.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return zero, fmt.Errorf("key %s: %w", key, ErrNotFound)
+		}
+		return zero, fmt.Errorf("failed to get key %s: %w", key, err)
+	}
+	return s.decode(key, data)
+}
+
+// Set stores the value for id with optional expiration
+func (s *Store[T]) Set(ctx context.Context, id string, value T, expiration time.Duration) error {
+	key := s.Key(id)
+	data, err := s.encode(key, value)
+	if err != nil {
+		return err
+	}
+
+	if err := s.manager.client.Set(ctx, key, data, expiration).Err(); err != nil {
+		return fmt.Errorf("failed to set key %s: %w", key, err)
+	}
+	return nil
+}
+
+// Update replaces the value stored for id with optional expiration, but only if
+// one is still stored. It reports whether the value was written.
+func (s *Store[T]) Update(ctx context.Context, id string, value T, expiration time.Duration) (bool, error) {
+	key := s.Key(id)
+	data, err := s.encode(key, value)
+	if err != nil {
+		return false, err
+	}
+
+	updated, err := s.manager.client.SetXX(ctx, key, data, expiration).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to update key %s: %w", key, err)
+	}
+	return updated, nil
+}
+
+// Exists checks if a value is stored for id
+func (s *Store[T]) Exists(ctx context.Context, id string) (bool, error) {
+	key := s.Key(id)
+	result, err := s.manager.client.Exists(ctx, key).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to check existence of key %s: %w", key, err)
+	}
+	return result > 0, nil
+}
+
+// MGet returns the values stored for ids in a single pipeline. Missing ids are
+// left out of the result; values that cannot be decoded are left out and
+// reported as ErrDecode alongside the values that could.
+func (s *Store[T]) MGet(ctx context.Context, ids ...string) (map[string]T, error) {
+	if len(ids) == 0 {
+		return map[string]T{}, nil
+	}
+
+	pipe := s.manager.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.Get(ctx, s.Key(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get %d keys in %s: %w", len(ids), s.namespace, err)
+	}
+
+	values := make(map[string]T, len(ids))
+	var errs []error
+	for i, cmd := range cmds {
+		data, err := cmd.Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to get key %s: %w", s.Key(ids[i]), err))
+			continue
+		}
+
+		value, err := s.decode(s.Key(ids[i]), data)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values[ids[i]] = value
+	}
+	return values, errors.Join(errs...)
+}
+
+// MSet stores several values with the same expiration in a single pipeline
+func (s *Store[T]) MSet(ctx context.Context, values map[string]T, expiration time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := s.manager.client.Pipeline()
+	for id, value := range values {
+		key := s.Key(id)
+		data, err := s.encode(key, value)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, key, data, expiration)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to set %d keys in %s: %w", len(values), s.namespace, err)
+	}
+	return nil
+}
+
+// Delete removes the values stored for ids and returns how many existed
+func (s *Store[T]) Delete(ctx context.Context, ids ...string) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// One DEL per key keeps the batch valid when keys live in different cluster slots
+	pipe := s.manager.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.Del(ctx, s.Key(id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, fmt.Errorf("failed to delete %d keys in %s: %w", len(ids), s.namespace, err)
+	}
+
+	var deleted int64
+	for _, cmd := range cmds {
+		deleted += cmd.Val()
+	}
+	return deleted, nil
+}
+
+// encode marshals a value for storage
+func (s *Store[T]) encode(key string, value T) ([]byte, error) {
+	data, err := s.codec.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value for key %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// decode unmarshals a stored value
+func (s *Store[T]) decode(key string, data []byte) (T, error) {
+	var value T
+	if err := s.codec.Unmarshal(data, &value); err != nil {
+		var zero T
+		return zero, fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,481 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultConsumerConcurrency is the default number of messages handled at once
+	DefaultConsumerConcurrency = 10
+
+	// DefaultConsumerBatchSize is the default number of messages read per request
+	DefaultConsumerBatchSize = 10
+
+	// DefaultConsumerBlock is the default time a read waits for new messages
+	DefaultConsumerBlock = 5 * time.Second
+
+	// DefaultClaimMinIdle is how long a message stays pending before it is retried
+	DefaultClaimMinIdle = time.Minute
+
+	// DefaultClaimInterval is how often pending messages are checked for retry
+	DefaultClaimInterval = 30 * time.Second
+
+	// DefaultMaxDeliveries is how often a message is tried before it is dead-lettered
+	DefaultMaxDeliveries = 5
+
+	// DeadLetterSuffix is appended to a stream name to form its default dead-letter stream
+	DeadLetterSuffix = ":dead"
+
+	// streamDataField is the entry field holding the encoded message
+	streamDataField = "data"
+
+	// consumerErrorBackoff is the delay after a failed read before retrying
+	consumerErrorBackoff = time.Second
+)
+
+// Stream appends typed messages to a Redis stream and consumes them with consumer groups
+type Stream[T any] struct {
+	manager *Manager
+	name    string
+	maxLen  int64
+}
+
+// StreamOption represents a function that configures a Stream
+type StreamOption func(*streamOptions)
+
+// streamOptions holds the settings shared by streams of every type
+type streamOptions struct {
+	maxLen int64
+}
+
+// WithMaxLen caps the stream at approximately maxLen entries
+func WithMaxLen(maxLen int64) StreamOption {
+	return func(o *streamOptions) {
+		o.maxLen = maxLen
+	}
+}
+
+// NewStream creates a new typed stream
+func NewStream[T any](manager *Manager, name string, opts ...StreamOption) *Stream[T] {
+	options := &streamOptions{}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Stream[T]{
+		manager: manager,
+		name:    name,
+		maxLen:  options.maxLen,
+	}
+}
+
+// Add appends a message and returns its entry id
+func (s *Stream[T]) Add(ctx context.Context, value T) (string, error) {
+	data, err := s.manager.codec.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message for stream %s: %w", s.name, err)
+	}
+
+	id, err := s.manager.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: s.name,
+		MaxLen: s.maxLen,
+		Approx: s.maxLen > 0,
+		Values: map[string]any{streamDataField: data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to add to stream %s: %w", s.name, err)
+	}
+	return id, nil
+}
+
+// Message is a stream entry delivered to a consumer
+type Message[T any] struct {
+	ID         string
+	Value      T
+	Deliveries int64
+}
+
+// Handler processes a stream message. Returning an error leaves the message
+// pending so that it is retried.
+type Handler[T any] func(ctx context.Context, message Message[T]) error
+
+// ConsumerOption represents a function that configures a Consumer
+type ConsumerOption func(*consumerOptions)
+
+// consumerOptions holds the settings shared by consumers of every type
+type consumerOptions struct {
+	concurrency   int
+	batchSize     int64
+	block         time.Duration
+	claimMinIdle  time.Duration
+	claimInterval time.Duration
+	maxDeliveries int64
+	deadLetter    string
+}
+
+// WithConcurrency sets how many messages are handled at once
+func WithConcurrency(concurrency int) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.concurrency = concurrency
+	}
+}
+
+// WithBatchSize sets how many messages are read per request
+func WithBatchSize(batchSize int64) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.batchSize = batchSize
+	}
+}
+
+// WithBlock sets how long a read waits for new messages
+func WithBlock(block time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.block = block
+	}
+}
+
+// WithClaim sets how long a message stays pending before it is retried and
+// how often pending messages are checked
+func WithClaim(minIdle, interval time.Duration) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.claimMinIdle = minIdle
+		o.claimInterval = interval
+	}
+}
+
+// WithMaxDeliveries sets how often a message is tried before it is dead-lettered
+func WithMaxDeliveries(maxDeliveries int64) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.maxDeliveries = maxDeliveries
+	}
+}
+
+// WithDeadLetter sets the stream that receives messages which cannot be processed
+func WithDeadLetter(stream string) ConsumerOption {
+	return func(o *consumerOptions) {
+		o.deadLetter = stream
+	}
+}
+
+// Consumer reads a stream as a member of a consumer group
+type Consumer[T any] struct {
+	stream   *Stream[T]
+	group    string
+	name     string
+	handler  Handler[T]
+	options  consumerOptions
+	slots    chan struct{}
+	inFlight sync.Map
+
+	loopCtx       context.Context
+	cancel        context.CancelFunc
+	handlerCtx    context.Context
+	handlerCancel context.CancelFunc
+	loops         sync.WaitGroup
+	handlers      sync.WaitGroup
+}
+
+// Consumer creates a consumer named name in group. Consumer names must be
+// unique within the group, for example the service instance id.
+func (s *Stream[T]) Consumer(group, name string, handler Handler[T], opts ...ConsumerOption) *Consumer[T] {
+	options := consumerOptions{
+		concurrency:   DefaultConsumerConcurrency,
+		batchSize:     DefaultConsumerBatchSize,
+		block:         DefaultConsumerBlock,
+		claimMinIdle:  DefaultClaimMinIdle,
+		claimInterval: DefaultClaimInterval,
+		maxDeliveries: DefaultMaxDeliveries,
+		deadLetter:    s.name + DeadLetterSuffix,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &Consumer[T]{
+		stream:  s,
+		group:   group,
+		name:    name,
+		handler: handler,
+		options: options,
+		slots:   make(chan struct{}, max(options.concurrency, 1)),
+	}
+}
+
+// Start creates the consumer group if needed and begins consuming in the
+// background. A new group only receives messages added after it is created.
+// Call Shutdown to stop.
+func (c *Consumer[T]) Start(ctx context.Context) error {
+	err := c.stream.manager.client.XGroupCreateMkStream(ctx, c.stream.name, c.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", c.group, c.stream.name, err)
+	}
+
+	c.loopCtx, c.cancel = context.WithCancel(ctx)
+	c.handlerCtx, c.handlerCancel = context.WithCancel(context.WithoutCancel(ctx))
+
+	c.loops.Add(2)
+	go c.readLoop()
+	go c.claimLoop()
+
+	c.stream.manager.logger.Info("Stream consumer started",
+		zap.String("stream", c.stream.name),
+		zap.String("group", c.group),
+		zap.String("consumer", c.name))
+	return nil
+}
+
+// Shutdown stops reading and waits for in-flight messages to be handled. If
+// ctx ends first, handlers are cancelled and their messages stay pending for
+// another consumer to claim.
+func (c *Consumer[T]) Shutdown(ctx context.Context) error {
+	if c.cancel == nil {
+		return nil
+	}
+	c.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		c.loops.Wait()
+		c.handlers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		c.handlerCancel()
+		return nil
+	case <-ctx.Done():
+		c.handlerCancel()
+		return fmt.Errorf("stream consumer %s did not stop in time: %w", c.name, ctx.Err())
+	}
+}
+
+// readLoop reads new messages for this consumer
+func (c *Consumer[T]) readLoop() {
+	defer c.loops.Done()
+
+	for c.loopCtx.Err() == nil {
+		streams, err := c.stream.manager.client.XReadGroup(c.loopCtx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.name,
+			Streams:  []string{c.stream.name, ">"},
+			Count:    c.options.batchSize,
+			Block:    c.options.block,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || c.loopCtx.Err() != nil {
+				continue
+			}
+			c.stream.manager.logger.Warn("Failed to read stream",
+				zap.String("stream", c.stream.name), zap.Error(err))
+			c.sleep(consumerErrorBackoff)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				c.dispatch(message, 1)
+			}
+		}
+	}
+}
+
+// claimLoop periodically takes over messages that stayed pending too long,
+// whether left by a failed handler or by a consumer that went away
+func (c *Consumer[T]) claimLoop() {
+	defer c.loops.Done()
+
+	ticker := time.NewTicker(c.options.claimInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.claim()
+		case <-c.loopCtx.Done():
+			return
+		}
+	}
+}
+
+// claim retries pending messages with XAUTOCLAIM
+func (c *Consumer[T]) claim() {
+	client := c.stream.manager.client
+	start := "0-0"
+	for c.loopCtx.Err() == nil {
+		messages, next, err := client.XAutoClaim(c.loopCtx, &redis.XAutoClaimArgs{
+			Stream:   c.stream.name,
+			Group:    c.group,
+			Consumer: c.name,
+			MinIdle:  c.options.claimMinIdle,
+			Start:    start,
+			Count:    c.options.batchSize,
+		}).Result()
+		if err != nil {
+			if c.loopCtx.Err() == nil {
+				c.stream.manager.logger.Warn("Failed to claim pending messages",
+					zap.String("stream", c.stream.name), zap.Error(err))
+			}
+			return
+		}
+
+		deliveries := c.deliveries(messages)
+		for _, message := range messages {
+			if message.Values == nil {
+				// The entry was trimmed from the stream while pending
+				c.ack(message.ID)
+				continue
+			}
+			c.dispatch(message, deliveries[message.ID])
+		}
+
+		if next == "0-0" || next == "" {
+			return
+		}
+		start = next
+	}
+}
+
+// deliveries looks up how often each claimed message has been delivered
+func (c *Consumer[T]) deliveries(messages []redis.XMessage) map[string]int64 {
+	counts := make(map[string]int64, len(messages))
+	if len(messages) == 0 {
+		return counts
+	}
+
+	pipe := c.stream.manager.client.Pipeline()
+	cmds := make([]*redis.XPendingExtCmd, len(messages))
+	for i, message := range messages {
+		cmds[i] = pipe.XPendingExt(c.loopCtx, &redis.XPendingExtArgs{
+			Stream: c.stream.name,
+			Group:  c.group,
+			Start:  message.ID,
+			End:    message.ID,
+			Count:  1,
+		})
+	}
+	if _, err := pipe.Exec(c.loopCtx); err != nil {
+		c.stream.manager.logger.Warn("Failed to read delivery counts",
+			zap.String("stream", c.stream.name), zap.Error(err))
+	}
+
+	for i, cmd := range cmds {
+		if pending := cmd.Val(); len(pending) > 0 {
+			counts[messages[i].ID] = pending[0].RetryCount
+		}
+	}
+	return counts
+}
+
+// dispatch hands a message to the handler pool, waiting for a free slot
+func (c *Consumer[T]) dispatch(message redis.XMessage, deliveries int64) {
+	if _, handling := c.inFlight.LoadOrStore(message.ID, struct{}{}); handling {
+		return
+	}
+
+	select {
+	case c.slots <- struct{}{}:
+	case <-c.loopCtx.Done():
+		// Left pending for another consumer to claim
+		c.inFlight.Delete(message.ID)
+		return
+	}
+
+	c.handlers.Add(1)
+	go func() {
+		defer func() {
+			c.inFlight.Delete(message.ID)
+			<-c.slots
+			c.handlers.Done()
+		}()
+		c.process(message, deliveries)
+	}()
+}
+
+// process decodes and handles a message, acknowledging it on success and
+// dead-lettering it once it cannot succeed
+func (c *Consumer[T]) process(message redis.XMessage, deliveries int64) {
+	data, _ := message.Values[streamDataField].(string)
+
+	var value T
+	if err := c.stream.manager.codec.Unmarshal([]byte(data), &value); err != nil {
+		c.deadLetter(message, deliveries, fmt.Errorf("%w: %w", ErrDecode, err))
+		return
+	}
+
+	err := c.handler(c.handlerCtx, Message[T]{ID: message.ID, Value: value, Deliveries: deliveries})
+	if err == nil {
+		c.ack(message.ID)
+		return
+	}
+
+	if deliveries >= c.options.maxDeliveries {
+		c.deadLetter(message, deliveries, err)
+		return
+	}
+	c.stream.manager.logger.Warn("Stream handler failed, message will be retried",
+		zap.String("stream", c.stream.name),
+		zap.String("id", message.ID),
+		zap.Int64("deliveries", deliveries),
+		zap.Error(err))
+}
+
+// ack acknowledges a message
+func (c *Consumer[T]) ack(id string) {
+	if err := c.stream.manager.client.XAck(c.handlerCtx, c.stream.name, c.group, id).Err(); err != nil {
+		c.stream.manager.logger.Warn("Failed to acknowledge message",
+			zap.String("stream", c.stream.name), zap.String("id", id), zap.Error(err))
+	}
+}
+
+// deadLetter moves a message to the dead-letter stream and acknowledges it
+func (c *Consumer[T]) deadLetter(message redis.XMessage, deliveries int64, cause error) {
+	err := c.stream.manager.client.XAdd(c.handlerCtx, &redis.XAddArgs{
+		Stream: c.options.deadLetter,
+		Values: map[string]any{
+			streamDataField: message.Values[streamDataField],
+			"stream":        c.stream.name,
+			"group":         c.group,
+			"id":            message.ID,
+			"deliveries":    deliveries,
+			"error":         cause.Error(),
+		},
+	}).Err()
+	if err != nil {
+		// Keep the message pending so it is not lost
+		c.stream.manager.logger.Error("Failed to dead-letter message",
+			zap.String("stream", c.stream.name), zap.String("id", message.ID), zap.Error(err))
+		return
+	}
+
+	c.stream.manager.logger.Warn("Message moved to dead-letter stream",
+		zap.String("stream", c.stream.name),
+		zap.String("deadLetter", c.options.deadLetter),
+		zap.String("id", message.ID),
+		zap.Int64("deliveries", deliveries),
+		zap.Error(cause))
+	c.ack(message.ID)
+}
+
+// sleep waits for d or until the consumer stops
+func (c *Consumer[T]) sleep(d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-c.loopCtx.Done():
+	}
+}
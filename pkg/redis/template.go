@@ -2,7 +2,7 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/redis/go-redis/v9"
@@ -11,12 +11,12 @@ import (
 
 // Template represents a Redis template for operations
 type Template struct {
-	client *redis.Client
+	client redis.UniversalClient
 	logger *zap.Logger
 }
 
 // NewTemplate creates a new Redis template
-func NewTemplate(client *redis.Client, logger *zap.Logger) *Template {
+func NewTemplate(client redis.UniversalClient, logger *zap.Logger) *Template {
 	return &Template{
 		client: client,
 		logger: logger,
@@ -25,12 +25,12 @@ func NewTemplate(client *redis.Client, logger *zap.Logger) *Template {
 
 // Set sets a key-value pair in Redis
 func (t *Template) Set(ctx context.Context, key string, value any) error {
-	jsonValue, err := json.Marshal(value)
+	data, err := DefaultCodec.Marshal(value)
 	if err != nil {
 		return fmt.Errorf("failed to marshal value: %w", err)
 	}
 
-	err = t.client.Set(ctx, key, jsonValue, 0).Err()
+	err = t.client.Set(ctx, key, data, 0).Err()
 	if err != nil {
 		return fmt.Errorf("failed to set key %s: %w", key, err)
 	}
@@ -40,17 +40,17 @@ func (t *Template) Set(ctx context.Context, key string, value any) error {
 
 // Get gets a value from Redis by key
 func (t *Template) Get(ctx context.Context, key string, value any) error {
-	jsonValue, err := t.client.Get(ctx, key).Bytes()
+	data, err := t.client.Get(ctx, key).Bytes()
 	if err != nil {
-		if err == redis.Nil {
-			return fmt.Errorf("key %s does not exist", key)
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("key %s: %w", key, ErrNotFound)
 		}
 		return fmt.Errorf("failed to get key %s: %w", key, err)
 	}
 
-	err = json.Unmarshal(jsonValue, value)
+	err = DefaultCodec.Unmarshal(data, value)
 	if err != nil {
-		return fmt.Errorf("failed to unmarshal value for key %s: %w", key, err)
+		return fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
 	}
 
 	return nil
@@ -0,0 +1,136 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultTxAttempts is the default number of times a conflicting transaction is tried
+	DefaultTxAttempts = 10
+
+	// DefaultTxBackoff is the default base delay between attempts, growing with each one
+	DefaultTxBackoff = 5 * time.Millisecond
+)
+
+// TxOption represents a function that configures a transaction
+type TxOption func(*txOptions)
+
+// txOptions holds the retry settings of a transaction
+type txOptions struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// WithTxAttempts sets how many times a transaction is tried before giving up
+// with ErrTxConflict. Values below 1 use DefaultTxAttempts.
+func WithTxAttempts(attempts int) TxOption {
+	return func(o *txOptions) {
+		o.attempts = attempts
+	}
+}
+
+// WithTxBackoff sets the base delay between attempts
+func WithTxBackoff(backoff time.Duration) TxOption {
+	return func(o *txOptions) {
+		o.backoff = backoff
+	}
+}
+
+// Tx is an optimistic transaction. Reads see the current values of the
+// watched keys and writes are queued on Pipeline, to be applied atomically
+// only if none of the watched keys changed in the meantime.
+type Tx struct {
+	manager  *Manager
+	tx       *redis.Tx
+	pipeline *Pipeline
+}
+
+// Get reads a value and unmarshals it into value.
+// It returns ErrNotFound if the key is missing and ErrDecode if it cannot be unmarshalled.
+func (t *Tx) Get(ctx context.Context, key string, value any) error {
+	data, err := t.tx.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return fmt.Errorf("key %s: %w", key, ErrNotFound)
+		}
+		return fmt.Errorf("failed to get key %s: %w", key, err)
+	}
+
+	if err := t.manager.codec.Unmarshal(data, value); err != nil {
+		return fmt.Errorf("failed to unmarshal value for key %s: %w: %w", key, ErrDecode, err)
+	}
+	return nil
+}
+
+// Client returns the underlying connection for reads without a typed helper
+func (t *Tx) Client() *redis.Tx {
+	return t.tx
+}
+
+// Pipeline returns the pipeline whose commands are applied atomically when
+// the transaction function returns nil
+func (t *Tx) Pipeline() *Pipeline {
+	if t.pipeline == nil {
+		t.pipeline = t.manager.newPipeline(t.tx.TxPipeline())
+	}
+	return t.pipeline
+}
+
+// Transaction watches keys, runs fn and applies the writes it queued with
+// MULTI/EXEC. If a watched key is modified before the writes are applied,
+// fn runs again with fresh values, so it must not have side effects outside
+// the transaction. It returns ErrTxConflict when every attempt conflicted
+// and the error of fn if it fails. On a cluster all keys must hash to the
+// same slot.
+func (m *Manager) Transaction(ctx context.Context, keys []string, fn func(ctx context.Context, tx *Tx) error, opts ...TxOption) error {
+	options := &txOptions{
+		attempts: DefaultTxAttempts,
+		backoff:  DefaultTxBackoff,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if options.attempts <= 0 {
+		options.attempts = DefaultTxAttempts
+	}
+
+	for attempt := 1; attempt <= options.attempts; attempt++ {
+		err := m.client.Watch(ctx, func(rtx *redis.Tx) error {
+			tx := &Tx{manager: m, tx: rtx}
+			if err := fn(ctx, tx); err != nil {
+				return err
+			}
+			if tx.pipeline == nil || tx.pipeline.Len() == 0 {
+				return nil
+			}
+			return tx.pipeline.Exec(ctx)
+		}, keys...)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+
+		if attempt == options.attempts {
+			break
+		}
+
+		m.logger.Debug("Redis transaction conflicted, retrying",
+			zap.Strings("keys", keys), zap.Int("attempt", attempt))
+
+		select {
+		case <-time.After(time.Duration(attempt) * options.backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return fmt.Errorf("transaction on keys %v failed after %d attempts: %w", keys, options.attempts, ErrTxConflict)
+}
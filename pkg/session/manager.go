@@ -0,0 +1,211 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultCookieName is the default name of the session cookie
+	DefaultCookieName = "MM_SESSION"
+
+	// DefaultIdleTimeout is how long a session lives without being used
+	DefaultIdleTimeout = 30 * time.Minute
+
+	// DefaultAbsoluteTimeout is how long a session lives at most
+	DefaultAbsoluteTimeout = 24 * time.Hour
+
+	// keyNamespace prefixes session keys in Redis
+	keyNamespace = "session"
+)
+
+// Manager issues, loads and saves sessions stored in Redis
+type Manager struct {
+	redis           *redis.Manager
+	store           *redis.Store[record]
+	logger          *zap.Logger
+	cookieName      string
+	cookiePath      string
+	cookieDomain    string
+	secure          bool
+	sameSite        http.SameSite
+	idleTimeout     time.Duration
+	absoluteTimeout time.Duration
+}
+
+// Option represents a function that configures the Manager
+type Option func(*Manager)
+
+// WithCookieName sets the name of the session cookie
+func WithCookieName(name string) Option {
+	return func(m *Manager) {
+		m.cookieName = name
+	}
+}
+
+// WithCookieScope sets the domain and path of the session cookie
+func WithCookieScope(domain, path string) Option {
+	return func(m *Manager) {
+		m.cookieDomain = domain
+		m.cookiePath = path
+	}
+}
+
+// WithSecureCookie controls the Secure attribute, which should only be turned off for local development
+func WithSecureCookie(secure bool) Option {
+	return func(m *Manager) {
+		m.secure = secure
+	}
+}
+
+// WithSameSite sets the SameSite attribute of the session cookie
+func WithSameSite(sameSite http.SameSite) Option {
+	return func(m *Manager) {
+		m.sameSite = sameSite
+	}
+}
+
+// WithTimeouts sets the idle timeout, which slides with every use, and the
+// absolute lifetime of a session
+func WithTimeouts(idle, absolute time.Duration) Option {
+	return func(m *Manager) {
+		m.idleTimeout = idle
+		m.absoluteTimeout = absolute
+	}
+}
+
+// NewManager creates a new session manager
+func NewManager(redisManager *redis.Manager, logger *zap.Logger, opts ...Option) *Manager {
+	manager := &Manager{
+		redis:           redisManager,
+		store:           redis.NewStore[record](redisManager, keyNamespace),
+		logger:          logger,
+		cookieName:      DefaultCookieName,
+		cookiePath:      "/",
+		secure:          true,
+		sameSite:        http.SameSiteLaxMode,
+		idleTimeout:     DefaultIdleTimeout,
+		absoluteTimeout: DefaultAbsoluteTimeout,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(manager)
+	}
+
+	return manager
+}
+
+// InvalidateUser deletes every session of a user, signing them out on all
+// devices, and returns how many sessions were removed
+func (m *Manager) InvalidateUser(ctx context.Context, userID string) (int64, error) {
+	indexKey := m.userIndexKey(userID)
+	ids, err := m.redis.Client().SMembers(ctx, indexKey).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list sessions of user %s: %w", userID, err)
+	}
+
+	deleted, err := m.store.Delete(ctx, ids...)
+	if err != nil {
+		return 0, err
+	}
+	if err := m.redis.Client().Del(ctx, indexKey).Err(); err != nil {
+		return deleted, fmt.Errorf("failed to delete session index of user %s: %w", userID, err)
+	}
+
+	m.logger.Info("Invalidated user sessions", zap.String("userId", userID), zap.Int64("sessions", deleted))
+	return deleted, nil
+}
+
+// save writes a used session back to Redis, extending its idle timeout
+func (m *Manager) save(ctx context.Context, s *Session) error {
+	if !s.loaded || (!s.exists && !s.dirty) {
+		return nil
+	}
+
+	ttl := m.ttl(s.record)
+	if ttl <= 0 {
+		return m.remove(ctx, s.id, s.record.UserID)
+	}
+
+	id := storeID(s.id)
+	written, err := m.write(ctx, id, s, ttl)
+	if err != nil {
+		return err
+	}
+	if !written {
+		// The session was invalidated while the request was running; keep it gone
+		m.logger.Debug("Session removed before it was saved", zap.String("userId", s.record.UserID))
+		return nil
+	}
+
+	if s.record.UserID == "" {
+		return nil
+	}
+
+	// The index outlives every session it lists; stale members are dropped on invalidation
+	indexKey := m.userIndexKey(s.record.UserID)
+	pipe := m.redis.Client().Pipeline()
+	pipe.SAdd(ctx, indexKey, id)
+	pipe.Expire(ctx, indexKey, m.absoluteTimeout)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to index session of user %s: %w", s.record.UserID, err)
+	}
+	return nil
+}
+
+// write stores a new session, or updates or extends an existing one only if it
+// still exists, and reports whether the session was written
+func (m *Manager) write(ctx context.Context, id string, s *Session, ttl time.Duration) (bool, error) {
+	switch {
+	case !s.exists:
+		if err := m.store.Set(ctx, id, s.record, ttl); err != nil {
+			return false, err
+		}
+		return true, nil
+	case s.dirty:
+		return m.store.Update(ctx, id, s.record, ttl)
+	default:
+		extended, err := m.redis.Client().Expire(ctx, m.store.Key(id), ttl).Result()
+		if err != nil {
+			return false, fmt.Errorf("failed to extend session: %w", err)
+		}
+		return extended, nil
+	}
+}
+
+// remove deletes a session and drops it from its user's index
+func (m *Manager) remove(ctx context.Context, sessionID, userID string) error {
+	id := storeID(sessionID)
+	if _, err := m.store.Delete(ctx, id); err != nil {
+		return err
+	}
+	if userID == "" {
+		return nil
+	}
+	if err := m.redis.Client().SRem(ctx, m.userIndexKey(userID), id).Err(); err != nil {
+		return fmt.Errorf("failed to remove session from index of user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// ttl returns how long a session may live from now: the idle timeout, capped
+// by what is left of its absolute lifetime
+func (m *Manager) ttl(r record) time.Duration {
+	return min(m.idleTimeout, time.Until(r.CreatedAt.Add(m.absoluteTimeout)))
+}
+
+// expired reports whether a stored session is past its absolute lifetime
+func (m *Manager) expired(r record) bool {
+	return m.ttl(r) <= 0
+}
+
+// userIndexKey returns the key of the set listing a user's sessions
+func (m *Manager) userIndexKey(userID string) string {
+	return redis.Namespace(keyNamespace, "user", userID)
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// newTestManager creates a session manager backed by an in-memory Redis server
+func newTestManager(t *testing.T) (*Manager, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	return NewManager(redis.NewManager(client, zap.NewNop()), zap.NewNop()), server
+}
+
+// newTestSession creates the session of a request carrying the cookie id
+func newTestSession(m *Manager, id string) *Session {
+	return &Session{
+		manager: m,
+		ctx:     context.Background(),
+		cookie:  &cookieWriter{manager: m, writer: httptest.NewRecorder()},
+		id:      id,
+	}
+}
+
+// login stores a new session of userID and returns its id
+func login(t *testing.T, m *Manager, userID string) string {
+	t.Helper()
+
+	s := newTestSession(m, "")
+	if err := s.Login(userID); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if err := m.save(context.Background(), s); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+	return s.id
+}
+
+func TestSaveAfterInvalidateUser(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(s *Session) error
+	}{
+		{name: "dirty", modify: func(s *Session) error { return s.Set("theme", "dark") }},
+		{name: "unchanged", modify: func(s *Session) error { _, err := s.UserID(); return err }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, server := newTestManager(t)
+			ctx := context.Background()
+			id := login(t, m, "42")
+
+			// A request loads the session while the user is signed out everywhere
+			s := newTestSession(m, id)
+			if err := tt.modify(s); err != nil {
+				t.Fatalf("modifying session error = %v", err)
+			}
+			if deleted, err := m.InvalidateUser(ctx, "42"); err != nil || deleted != 1 {
+				t.Fatalf("InvalidateUser() = %d, %v, want 1, nil", deleted, err)
+			}
+
+			if err := m.save(ctx, s); err != nil {
+				t.Fatalf("save() error = %v", err)
+			}
+			if server.Exists(m.store.Key(storeID(id))) {
+				t.Error("invalidated session written back")
+			}
+			if server.Exists(m.userIndexKey("42")) {
+				t.Error("invalidated session re-added to the user index")
+			}
+		})
+	}
+}
+
+func TestSaveExistingSession(t *testing.T) {
+	m, server := newTestManager(t)
+	ctx := context.Background()
+	id := login(t, m, "42")
+
+	s := newTestSession(m, id)
+	if err := s.Set("theme", "dark"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := m.save(ctx, s); err != nil {
+		t.Fatalf("save() error = %v", err)
+	}
+
+	reloaded := newTestSession(m, id)
+	if value, _, err := reloaded.Get("theme"); err != nil || value != "dark" {
+		t.Fatalf("Get() = %v, %v, want dark, nil", value, err)
+	}
+	if members, _ := server.Members(m.userIndexKey("42")); len(members) != 1 {
+		t.Errorf("user index = %v, want the session", members)
+	}
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ContextKey is the gin context key holding the request's session
+const ContextKey = "session"
+
+// Middleware attaches a lazily loaded session to every request and saves it
+// after the handler if it was used
+func (m *Manager) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s := &Session{
+			manager: m,
+			ctx:     c.Request.Context(),
+			cookie:  &cookieWriter{manager: m, writer: c.Writer},
+		}
+		if cookie, err := c.Cookie(m.cookieName); err == nil {
+			s.id = cookie
+		}
+
+		c.Set(ContextKey, s)
+		c.Next()
+
+		if err := m.save(c.Request.Context(), s); err != nil {
+			m.logger.Error("Failed to save session", zap.Error(err))
+		}
+	}
+}
+
+// Get retrieves the session from the context
+func Get(c *gin.Context) (*Session, bool) {
+	value, exists := c.Get(ContextKey)
+	if !exists {
+		return nil, false
+	}
+
+	s, ok := value.(*Session)
+	return s, ok
+}
+
+// cookieWriter sets the session cookie on the response
+type cookieWriter struct {
+	manager *Manager
+	writer  http.ResponseWriter
+}
+
+// set issues a cookie for a session id. The cookie has no expiry; the
+// session's lifetime is enforced in Redis.
+func (w *cookieWriter) set(id string) {
+	http.SetCookie(w.writer, w.cookie(id, 0))
+}
+
+// clear tells the client to drop the session cookie
+func (w *cookieWriter) clear() {
+	http.SetCookie(w.writer, w.cookie("", -1))
+}
+
+// cookie builds the session cookie
+func (w *cookieWriter) cookie(value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     w.manager.cookieName,
+		Value:    value,
+		Path:     w.manager.cookiePath,
+		Domain:   w.manager.cookieDomain,
+		MaxAge:   maxAge,
+		Secure:   w.manager.secure,
+		HttpOnly: true,
+		SameSite: w.manager.sameSite,
+	}
+}
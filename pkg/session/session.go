@@ -0,0 +1,184 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/redis"
+)
+
+// record is the session state stored in Redis
+type record struct {
+	UserID    string         `json:"userId,omitempty"`
+	Values    map[string]any `json:"values,omitempty"`
+	CreatedAt time.Time      `json:"createdAt"`
+}
+
+// Session is the server-side state of one client. It is loaded from Redis on
+// first use and saved after the request if it was used.
+type Session struct {
+	manager *Manager
+	ctx     context.Context
+	cookie  *cookieWriter
+	id      string
+	record  record
+	loaded  bool
+	exists  bool
+	dirty   bool
+}
+
+// ID returns the session id, or "" for a new session that has not been saved
+func (s *Session) ID() string {
+	if !s.exists && !s.dirty {
+		return ""
+	}
+	return s.id
+}
+
+// Get returns a session value. Numbers read back from Redis may be float64
+// depending on the codec.
+func (s *Session) Get(key string) (any, bool, error) {
+	if err := s.load(); err != nil {
+		return nil, false, err
+	}
+	value, exists := s.record.Values[key]
+	return value, exists, nil
+}
+
+// Set stores a session value
+func (s *Session) Set(key string, value any) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.record.Values == nil {
+		s.record.Values = make(map[string]any)
+	}
+	s.record.Values[key] = value
+	return s.markDirty()
+}
+
+// Delete removes a session value
+func (s *Session) Delete(key string) error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if _, exists := s.record.Values[key]; exists {
+		delete(s.record.Values, key)
+		return s.markDirty()
+	}
+	return nil
+}
+
+// UserID returns the user the session belongs to, or "" if it is anonymous
+func (s *Session) UserID() (string, error) {
+	if err := s.load(); err != nil {
+		return "", err
+	}
+	return s.record.UserID, nil
+}
+
+// Login binds the session to a user and regenerates its id to prevent session fixation
+func (s *Session) Login(userID string) error {
+	if err := s.Regenerate(); err != nil {
+		return err
+	}
+	s.record.UserID = userID
+	return nil
+}
+
+// Regenerate moves the session data to a new id and removes the old one
+func (s *Session) Regenerate() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.exists {
+		if err := s.manager.remove(s.ctx, s.id, s.record.UserID); err != nil {
+			return err
+		}
+	}
+
+	s.exists = false
+	s.id = ""
+	return s.markDirty()
+}
+
+// Destroy deletes the session and clears the cookie
+func (s *Session) Destroy() error {
+	if err := s.load(); err != nil {
+		return err
+	}
+	if s.exists {
+		if err := s.manager.remove(s.ctx, s.id, s.record.UserID); err != nil {
+			return err
+		}
+	}
+
+	s.record = record{}
+	s.exists = false
+	s.dirty = false
+	s.cookie.clear()
+	return nil
+}
+
+// load reads the session from Redis the first time it is needed
+func (s *Session) load() error {
+	if s.loaded {
+		return nil
+	}
+	s.loaded = true
+
+	if s.id != "" {
+		stored, err := s.manager.store.Get(s.ctx, storeID(s.id))
+		switch {
+		case err == nil && !s.manager.expired(stored):
+			s.record = stored
+			s.exists = true
+			return nil
+		case err != nil && !errors.Is(err, redis.ErrNotFound):
+			s.loaded = false
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+	}
+
+	// Unknown or expired ids are never reused so that clients cannot choose their id
+	s.id = ""
+	s.record = record{CreatedAt: time.Now()}
+	return nil
+}
+
+// markDirty schedules a save, issuing an id and cookie for new sessions
+func (s *Session) markDirty() error {
+	if s.id == "" {
+		id, err := newID()
+		if err != nil {
+			return err
+		}
+		s.id = id
+		s.record.CreatedAt = time.Now()
+		s.cookie.set(s.id)
+	}
+	s.dirty = true
+	return nil
+}
+
+// newID returns a random session id
+func newID() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// storeID derives the Redis id of a session so that ids readable from Redis
+// cannot be replayed as cookies
+func storeID(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(sum[:])
+}
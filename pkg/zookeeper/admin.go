@@ -0,0 +1,158 @@
+package zookeeper
+
+import (
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+const (
+	// AnyVersion disables the version check on writes
+	AnyVersion int32 = -1
+
+	// maxUpdateAttempts bounds the read-modify-write retries of key updates
+	maxUpdateAttempts = 5
+)
+
+// ErrVersionConflict is returned when a config node changed since it was read
+var ErrVersionConflict = errors.New("config version conflict")
+
+// ConfigDocument is a decoded config node together with its znode version
+type ConfigDocument struct {
+	Path    string
+	Version int32
+	Format  Format
+	Data    map[string]any
+}
+
+// GetConfigDocument reads and decodes the config document stored at nodePath
+func (c *Client) GetConfigDocument(nodePath string) (*ConfigDocument, error) {
+	data, stat, err := c.Get(nodePath)
+	if err != nil {
+		return nil, err
+	}
+
+	config, format, err := DecodeConfigFormat(data, c.config.Format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config document at %s: %w", nodePath, err)
+	}
+
+	return &ConfigDocument{
+		Path:    nodePath,
+		Version: stat.Version,
+		Format:  format,
+		Data:    config,
+	}, nil
+}
+
+// ReplaceConfigDocument writes a whole config document in the configured
+// format. The write only succeeds if the node is still at the expected version;
+// pass AnyVersion to skip the check. A missing node is created when the
+// version is AnyVersion.
+func (c *Client) ReplaceConfigDocument(nodePath string, config map[string]any, version int32) (int32, error) {
+	return c.replaceConfigDocument(nodePath, config, version, c.config.Format)
+}
+
+// replaceConfigDocument writes a whole config document in the given format
+func (c *Client) replaceConfigDocument(nodePath string, config map[string]any, version int32, format Format) (int32, error) {
+	data, err := EncodeConfigFormat(config, format)
+	if err != nil {
+		return 0, err
+	}
+
+	stat, err := c.conn.Set(c.fullPath(nodePath), data, version)
+	if err == zk.ErrNoNode && version == AnyVersion {
+		return 0, c.createConfigDocument(nodePath, data)
+	}
+	if err == zk.ErrBadVersion {
+		return 0, fmt.Errorf("%w: %s is no longer at version %d", ErrVersionConflict, nodePath, version)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write config node %s: %w", nodePath, err)
+	}
+
+	c.logger.Info("Config document updated",
+		zap.String("path", nodePath),
+		zap.Int32("version", stat.Version))
+	return stat.Version, nil
+}
+
+// createConfigDocument creates a config node and its missing parents. It
+// returns ErrVersionConflict if the node was created concurrently.
+func (c *Client) createConfigDocument(nodePath string, data []byte) error {
+	fullPath := c.fullPath(nodePath)
+	if err := c.ensurePath(path.Dir(fullPath)); err != nil {
+		return err
+	}
+
+	_, err := c.conn.Create(fullPath, data, 0, c.acl())
+	if err == zk.ErrNodeExists {
+		return fmt.Errorf("%w: %s was created concurrently", ErrVersionConflict, nodePath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create config node %s: %w", nodePath, err)
+	}
+
+	c.logger.Info("Config document created", zap.String("path", nodePath))
+	return nil
+}
+
+// SetConfigKey sets a single key, which may be a dotted path, in the config
+// document at nodePath, creating the node if it does not exist. Concurrent
+// edits are detected through the node version and the update is retried
+// against the latest document.
+func (c *Client) SetConfigKey(nodePath, key string, value any) error {
+	return c.updateConfigDocument(nodePath, func(config map[string]any) error {
+		return setPath(config, key, value)
+	})
+}
+
+// DeleteConfigKey removes a single key, which may be a dotted path, from the
+// config document at nodePath
+func (c *Client) DeleteConfigKey(nodePath, key string) error {
+	return c.updateConfigDocument(nodePath, func(config map[string]any) error {
+		if !deletePath(config, key) {
+			return fmt.Errorf("key %s not found in %s", key, nodePath)
+		}
+		return nil
+	})
+}
+
+// updateConfigDocument applies a mutation with optimistic concurrency
+func (c *Client) updateConfigDocument(nodePath string, mutate func(map[string]any) error) error {
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		document, err := c.GetConfigDocument(nodePath)
+		missing := errors.Is(err, zk.ErrNoNode)
+		if missing {
+			document = &ConfigDocument{Path: nodePath, Format: c.config.Format, Data: map[string]any{}}
+		} else if err != nil {
+			return err
+		}
+
+		if err := mutate(document.Data); err != nil {
+			return err
+		}
+
+		if missing {
+			var data []byte
+			data, err = EncodeConfigFormat(document.Data, document.Format)
+			if err == nil {
+				err = c.createConfigDocument(nodePath, data)
+			}
+		} else {
+			// Write back in the format the document was read in
+			_, err = c.replaceConfigDocument(nodePath, document.Data, document.Version, document.Format)
+		}
+		if errors.Is(err, ErrVersionConflict) {
+			c.logger.Warn("Config document changed concurrently, retrying",
+				zap.String("path", nodePath),
+				zap.Int("attempt", attempt+1))
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("%w: gave up updating %s after %d attempts", ErrVersionConflict, nodePath, maxUpdateAttempts)
+}
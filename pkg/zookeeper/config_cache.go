@@ -1,95 +1,319 @@
 package zookeeper
 
 import (
+	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"sync"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.uber.org/zap"
 )
 
+// Reasons recorded when a config document is rejected
+const (
+	rejectDecode     = "decode"
+	rejectValidation = "validation"
+)
+
 // ConfigCache manages the caching of service and common configurations
 type ConfigCache struct {
-	serviceConfig map[string]any
-	commonConfig  map[string]any
-	mu            sync.RWMutex
-	logger        *zap.Logger
+	serviceConfig    map[string]any
+	commonConfig     map[string]any
+	serviceVersion   ConfigVersion
+	commonVersion    ConfigVersion
+	serviceValidator Validator
+	commonValidator  Validator
+	history          []ConfigHistoryEntry
+	historySize      int
+	rejections       metric.Int64Counter
+	mu               sync.RWMutex
+	logger           *zap.Logger
 }
 
 // NewConfigCache creates a new ConfigCache instance
 func NewConfigCache(logger *zap.Logger) *ConfigCache {
+	rejections, err := otel.Meter(meterName).Int64Counter("zookeeper.config.rejections",
+		metric.WithDescription("Config documents rejected by decoding or validation"))
+	if err != nil {
+		logger.Warn("Failed to create config rejection counter", zap.Error(err))
+	}
+
 	return &ConfigCache{
 		serviceConfig: make(map[string]any),
 		commonConfig:  make(map[string]any),
+		historySize:   DefaultHistorySize,
+		rejections:    rejections,
 		logger:        logger,
 	}
 }
 
-// UpdateServiceConfig updates the service configuration cache
-func (cc *ConfigCache) UpdateServiceConfig(data []byte) error {
-	decodedData, err := base64.StdEncoding.DecodeString(string(data))
-	if err != nil {
-		cc.logger.Warn("Failed to decode service config", zap.Error(err))
-		return err
+// SetValidator registers the validator that service or common config documents
+// must pass before they replace the cached snapshot
+func (cc *ConfigCache) SetValidator(isCommon bool, validator Validator) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if isCommon {
+		cc.commonValidator = validator
+	} else {
+		cc.serviceValidator = validator
 	}
+}
 
-	var config map[string]any
-	if err := json.Unmarshal(decodedData, &config); err != nil {
-		cc.logger.Warn("Failed to parse service config", zap.Error(err))
-		return err
+// validate runs the registered validator and records rejections. The cached
+// snapshot is left untouched when validation fails.
+func (cc *ConfigCache) validate(isCommon bool, config map[string]any) error {
+	cc.mu.RLock()
+	validator := cc.serviceValidator
+	if isCommon {
+		validator = cc.commonValidator
 	}
+	cc.mu.RUnlock()
 
-	cc.mu.Lock()
-	cc.serviceConfig = config
-	cc.mu.Unlock()
+	if validator == nil {
+		return nil
+	}
+
+	if err := validator.Validate(config); err != nil {
+		return cc.reject(isCommon, rejectValidation, err)
+	}
 	return nil
 }
 
+// reject records a config document that could not be decoded or failed
+// validation. The cached snapshot is left untouched.
+func (cc *ConfigCache) reject(isCommon bool, reason string, err error) error {
+	kind := configKind(isCommon)
+	cc.logger.Error("Rejected invalid config, keeping last good snapshot",
+		zap.String("kind", kind),
+		zap.String("reason", reason),
+		zap.Error(err))
+	if cc.rejections != nil {
+		cc.rejections.Add(context.Background(), 1,
+			metric.WithAttributes(
+				attribute.String("kind", kind),
+				attribute.String("reason", reason)))
+	}
+	return fmt.Errorf("%w: %s config: %v", ErrInvalidConfig, kind, err)
+}
+
+// UpdateServiceConfig updates the service configuration cache
+func (cc *ConfigCache) UpdateServiceConfig(data []byte) error {
+	return cc.update(false, data)
+}
+
 // UpdateCommonConfig updates the common configuration cache
 func (cc *ConfigCache) UpdateCommonConfig(data []byte) error {
-	decodedData, err := base64.StdEncoding.DecodeString(string(data))
+	return cc.update(true, data)
+}
+
+// update decodes a raw config document, detecting its format, and stores it.
+// An empty document leaves the cached snapshot unchanged.
+func (cc *ConfigCache) update(isCommon bool, data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		cc.logger.Warn("Ignoring empty config, keeping last snapshot", zap.String("kind", configKind(isCommon)))
+		return nil
+	}
+
+	config, _, err := DecodeConfigFormat(data, FormatAuto)
 	if err != nil {
-		cc.logger.Warn("Failed to decode common config", zap.Error(err))
-		return err
+		return cc.reject(isCommon, rejectDecode, err)
 	}
+	return cc.store(isCommon, config, ConfigVersion{Hash: hashConfig(data)})
+}
 
-	var config map[string]any
-	if err := json.Unmarshal(decodedData, &config); err != nil {
-		cc.logger.Warn("Failed to parse common config", zap.Error(err))
+// store validates and swaps in a decoded config snapshot, recording a history
+// entry when its content changed
+func (cc *ConfigCache) store(isCommon bool, config map[string]any, version ConfigVersion) error {
+	kind := configKind(isCommon)
+	if err := cc.validate(isCommon, config); err != nil {
 		return err
 	}
+	version.LoadedAt = time.Now()
 
 	cc.mu.Lock()
-	cc.commonConfig = config
+	previous, previousVersion := cc.serviceConfig, cc.serviceVersion
+	if isCommon {
+		previous, previousVersion = cc.commonConfig, cc.commonVersion
+		cc.commonConfig, cc.commonVersion = config, version
+	} else {
+		cc.serviceConfig, cc.serviceVersion = config, version
+	}
+
+	if previousVersion.Hash == version.Hash {
+		cc.mu.Unlock()
+		return nil
+	}
+
+	changes := DiffConfig(previous, config)
+	cc.recordHistory(ConfigHistoryEntry{
+		Kind:          kind,
+		ConfigVersion: version,
+		Changes:       keyChanges(changes),
+	})
 	cc.mu.Unlock()
+
+	cc.logger.Info("Config snapshot loaded",
+		zap.String("kind", kind),
+		zap.Int32("version", version.Version),
+		zap.String("profile", version.Profile),
+		zap.Int32("profile_version", version.ProfileVersion),
+		zap.Int64("mzxid", version.Mzxid),
+		zap.String("hash", version.ShortHash()),
+		zap.Int("changes", len(changes)))
 	return nil
 }
 
-// GetServiceConfig retrieves a value from service configuration
+// getServiceConfig retrieves a value from service configuration
 func (cc *ConfigCache) getServiceConfig(key string) (any, bool) {
 	cc.mu.RLock()
 	defer cc.mu.RUnlock()
-	value, exists := cc.serviceConfig[key]
-	return value, exists
+	return lookupPath(cc.serviceConfig, key)
 }
 
-// GetCommonConfig retrieves a value from common configuration
+// getCommonConfig retrieves a value from common configuration
 func (cc *ConfigCache) getCommonConfig(key string) (any, bool) {
 	cc.mu.RLock()
 	defer cc.mu.RUnlock()
-	value, exists := cc.commonConfig[key]
-	return value, exists
+	return lookupPath(cc.commonConfig, key)
 }
 
-// GetConfig retrieves a config based on type
+// GetConfig retrieves a config based on type. Keys may be dotted paths such as
+// REDIS_CONFIG.host; service lookups fall through to common configuration when
+// the key is missing from the service node.
 func (cc *ConfigCache) GetConfig(isCommon bool, key string) (any, bool) {
-	cc.mu.RLock()
-	defer cc.mu.RUnlock()
-
 	if isCommon {
 		return cc.getCommonConfig(key)
 	}
-	
-	return cc.getServiceConfig(key)
 
-}
\ No newline at end of file
+	if value, exists := cc.getServiceConfig(key); exists {
+		return value, true
+	}
+	return cc.getCommonConfig(key)
+}
+
+// DecodeConfig decodes a base64 encoded JSON config document
+func DecodeConfig(data []byte) (map[string]any, error) {
+	decodedData, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	var config map[string]any
+	if err := json.Unmarshal(decodedData, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return config, nil
+}
+
+// EncodeConfig encodes a config document as base64 encoded JSON
+func EncodeConfig(config map[string]any) ([]byte, error) {
+	jsonData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return []byte(base64.StdEncoding.EncodeToString(jsonData)), nil
+}
+
+// mergeConfig returns a copy of base with overlay deep-merged on top; nested
+// objects are merged key by key and any other overlay value replaces the base
+func mergeConfig(base, overlay map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(overlay))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range overlay {
+		baseChild, baseIsMap := merged[key].(map[string]any)
+		overlayChild, overlayIsMap := value.(map[string]any)
+		if baseIsMap && overlayIsMap {
+			merged[key] = mergeConfig(baseChild, overlayChild)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// LookupConfig resolves a plain or dotted key against a config document
+func LookupConfig(config map[string]any, key string) (any, bool) {
+	return lookupPath(config, key)
+}
+
+// lookupPath resolves a key against a config map. An exact key match wins,
+// otherwise the key is split on dots and walked through nested maps.
+func lookupPath(config map[string]any, key string) (any, bool) {
+	if value, exists := config[key]; exists {
+		return value, true
+	}
+
+	var current any = config
+	for _, part := range strings.Split(key, ".") {
+		node, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = node[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// setPath sets a value in a config map, creating nested maps for dotted keys.
+// An existing top-level key with the exact name is updated in place.
+func setPath(config map[string]any, key string, value any) error {
+	if _, exists := config[key]; exists || !strings.Contains(key, ".") {
+		config[key] = value
+		return nil
+	}
+
+	parts := strings.Split(key, ".")
+	node := config
+	for _, part := range parts[:len(parts)-1] {
+		child, exists := node[part]
+		if !exists {
+			next := make(map[string]any)
+			node[part] = next
+			node = next
+			continue
+		}
+		next, ok := child.(map[string]any)
+		if !ok {
+			return fmt.Errorf("cannot set %s: %s is not an object", key, part)
+		}
+		node = next
+	}
+	node[parts[len(parts)-1]] = value
+	return nil
+}
+
+// deletePath removes a value from a config map and reports whether it existed
+func deletePath(config map[string]any, key string) bool {
+	if _, exists := config[key]; exists {
+		delete(config, key)
+		return true
+	}
+
+	parts := strings.Split(key, ".")
+	node := config
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := node[part].(map[string]any)
+		if !ok {
+			return false
+		}
+		node = next
+	}
+	if _, exists := node[parts[len(parts)-1]]; !exists {
+		return false
+	}
+	delete(node, parts[len(parts)-1])
+	return true
+}
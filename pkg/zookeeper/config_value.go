@@ -0,0 +1,70 @@
+package zookeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ConfigValue decodes a config key into T and keeps the result until the
+// config snapshot changes, so that hot paths do not decode the same document
+// on every call
+type ConfigValue[T any] struct {
+	client   *Client
+	key      string
+	isCommon bool
+
+	mu      sync.Mutex
+	stamp   string
+	loaded  bool
+	value   T
+	present bool
+	err     error
+}
+
+// NewConfigValue creates a cached decoder for a config key. Service lookups
+// fall through to common configuration like GetOrDefault.
+func NewConfigValue[T any](client *Client, key string, isCommon bool) *ConfigValue[T] {
+	return &ConfigValue[T]{
+		client:   client,
+		key:      key,
+		isCommon: isCommon,
+	}
+}
+
+// Get returns the decoded value and whether the key is set. The value is
+// shared between callers and must not be modified.
+func (v *ConfigValue[T]) Get() (T, bool, error) {
+	stamp := v.client.cache.Version(true).Hash
+	if !v.isCommon {
+		stamp = v.client.cache.Version(false).Hash + ":" + stamp
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.loaded || v.stamp != stamp {
+		v.value, v.present, v.err = v.decode()
+		v.stamp = stamp
+		v.loaded = true
+	}
+	return v.value, v.present, v.err
+}
+
+// decode reads the key from the cache and decodes it into T
+func (v *ConfigValue[T]) decode() (T, bool, error) {
+	var value T
+	raw, exists := v.client.cache.GetConfig(v.isCommon, v.key)
+	if !exists || raw == nil {
+		return value, false, nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return value, true, fmt.Errorf("failed to marshal config key %s: %w", v.key, err)
+	}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, true, fmt.Errorf("invalid format of config key %s: %w", v.key, err)
+	}
+	return value, true, nil
+}
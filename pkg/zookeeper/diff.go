@@ -0,0 +1,73 @@
+package zookeeper
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ChangeType describes how a config key changed between two documents
+type ChangeType string
+
+// Supported change types
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// ConfigChange is a single key-level difference between two config documents
+type ConfigChange struct {
+	Key      string     `json:"key"`
+	Type     ChangeType `json:"type"`
+	OldValue any        `json:"oldValue,omitempty"`
+	NewValue any        `json:"newValue,omitempty"`
+}
+
+// DiffConfig compares two config documents key by key. Nested objects are
+// flattened into dotted keys so that a change deep inside REDIS_CONFIG is
+// reported as REDIS_CONFIG.host rather than the whole object.
+func DiffConfig(oldConfig, newConfig map[string]any) []ConfigChange {
+	oldFlat := flattenConfig(oldConfig)
+	newFlat := flattenConfig(newConfig)
+
+	var changes []ConfigChange
+	for key, oldValue := range oldFlat {
+		newValue, exists := newFlat[key]
+		if !exists {
+			changes = append(changes, ConfigChange{Key: key, Type: ChangeRemoved, OldValue: oldValue})
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, ConfigChange{Key: key, Type: ChangeModified, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for key, newValue := range newFlat {
+		if _, exists := oldFlat[key]; !exists {
+			changes = append(changes, ConfigChange{Key: key, Type: ChangeAdded, NewValue: newValue})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// flattenConfig converts nested maps into a single level map with dotted keys
+func flattenConfig(config map[string]any) map[string]any {
+	flat := make(map[string]any)
+	flattenInto(flat, "", config)
+	return flat
+}
+
+// flattenInto recursively copies values from config into flat under prefix
+func flattenInto(flat map[string]any, prefix string, config map[string]any) {
+	for key, value := range config {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			flattenInto(flat, key, nested)
+			continue
+		}
+		flat[key] = value
+	}
+}
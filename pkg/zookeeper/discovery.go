@@ -0,0 +1,185 @@
+package zookeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+// watchRetryInterval is the delay before re-establishing a failed watch
+const watchRetryInterval = 2 * time.Second
+
+// Discovery watches the registered instances of a service and balances across them
+type Discovery struct {
+	client      *Client
+	serviceName string
+	instances   []ServiceInstance
+	listeners   []func([]ServiceInstance)
+	counter     atomic.Uint64
+	mu          sync.RWMutex
+	stopChan    chan struct{}
+	stopOnce    sync.Once
+}
+
+// NewDiscovery creates a discovery client for the given service and starts watching it
+func (c *Client) NewDiscovery(serviceName string) (*Discovery, error) {
+	if serviceName == "" {
+		return nil, fmt.Errorf("service name is required for discovery")
+	}
+
+	d := &Discovery{
+		client:      c,
+		serviceName: serviceName,
+		stopChan:    make(chan struct{}),
+	}
+
+	events, err := d.refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	go d.watch(events)
+
+	return d, nil
+}
+
+// refresh reloads the instance list and returns a watch for the next change
+func (d *Discovery) refresh() (<-chan zk.Event, error) {
+	servicePath := d.client.fullPath(path.Join(ServicesBasePath, d.serviceName))
+
+	children, _, events, err := d.client.conn.ChildrenW(servicePath)
+	if err == zk.ErrNoNode {
+		// Wait for the first instance to create the service node
+		_, _, events, err = d.client.conn.ExistsW(servicePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to watch node %s: %w", servicePath, err)
+		}
+		d.update(nil)
+		return events, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to watch children of node %s: %w", servicePath, err)
+	}
+
+	sort.Strings(children)
+	instances := make([]ServiceInstance, 0, len(children))
+	for _, child := range children {
+		data, _, err := d.client.conn.Get(path.Join(servicePath, child))
+		if err == zk.ErrNoNode {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get instance %s: %w", child, err)
+		}
+
+		var instance ServiceInstance
+		if err := json.Unmarshal(data, &instance); err != nil {
+			d.client.logger.Warn("Skipping malformed service instance",
+				zap.String("service", d.serviceName),
+				zap.String("instance", child),
+				zap.Error(err))
+			continue
+		}
+		instance.ID = child
+		instances = append(instances, instance)
+	}
+
+	d.update(instances)
+	return events, nil
+}
+
+// update stores the instance list and notifies listeners
+func (d *Discovery) update(instances []ServiceInstance) {
+	d.mu.Lock()
+	d.instances = instances
+	listeners := append([]func([]ServiceInstance){}, d.listeners...)
+	d.mu.Unlock()
+
+	for _, listener := range listeners {
+		listener(instances)
+	}
+}
+
+// watch keeps the instance list current until the discovery or its client
+// is closed
+func (d *Discovery) watch(events <-chan zk.Event) {
+	for {
+		select {
+		case <-events:
+		case <-d.stopChan:
+			return
+		case <-d.client.stopChan:
+			return
+		}
+
+		for {
+			var err error
+			events, err = d.refresh()
+			if err == nil {
+				break
+			}
+
+			d.client.logger.Warn("Failed to refresh service instances",
+				zap.String("service", d.serviceName),
+				zap.Error(err))
+
+			select {
+			case <-time.After(watchRetryInterval):
+			case <-d.stopChan:
+				return
+			case <-d.client.stopChan:
+				return
+			}
+		}
+	}
+}
+
+// Instances returns a snapshot of the currently registered instances
+func (d *Discovery) Instances() []ServiceInstance {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]ServiceInstance(nil), d.instances...)
+}
+
+// Next returns an instance using round-robin selection
+func (d *Discovery) Next() (ServiceInstance, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if len(d.instances) == 0 {
+		return ServiceInstance{}, fmt.Errorf("no instances available for service %s", d.serviceName)
+	}
+	index := (d.counter.Add(1) - 1) % uint64(len(d.instances))
+	return d.instances[index], nil
+}
+
+// Random returns a randomly selected instance
+func (d *Discovery) Random() (ServiceInstance, error) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if len(d.instances) == 0 {
+		return ServiceInstance{}, fmt.Errorf("no instances available for service %s", d.serviceName)
+	}
+	return d.instances[rand.Intn(len(d.instances))], nil
+}
+
+// OnChange registers a callback invoked with the new instance list on every change
+func (d *Discovery) OnChange(listener func([]ServiceInstance)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.listeners = append(d.listeners, listener)
+}
+
+// Close stops watching the service
+func (d *Discovery) Close() {
+	d.stopOnce.Do(func() {
+		close(d.stopChan)
+	})
+}
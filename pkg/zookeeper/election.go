@@ -0,0 +1,113 @@
+package zookeeper
+
+import (
+	"context"
+	"path"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ElectionBasePath is the root node under which election candidates register
+const ElectionBasePath = "/election"
+
+// LeaderCallbacks are invoked as an Election gains and loses leadership
+type LeaderCallbacks struct {
+	// OnStartedLeading runs in its own goroutine with a context that is
+	// cancelled as soon as leadership is lost
+	OnStartedLeading func(ctx context.Context)
+
+	// OnStoppedLeading runs after leadership is lost and OnStartedLeading has returned
+	OnStoppedLeading func()
+}
+
+// Election runs leader election among all instances using the same name
+type Election struct {
+	client    *Client
+	name      string
+	path      string
+	callbacks LeaderCallbacks
+	leader    atomic.Bool
+}
+
+// NewElection creates a leader election under /election/<name>
+func (c *Client) NewElection(name string, callbacks LeaderCallbacks) *Election {
+	return &Election{
+		client:    c,
+		name:      name,
+		path:      path.Join(ElectionBasePath, name),
+		callbacks: callbacks,
+	}
+}
+
+// Run campaigns for leadership until ctx is cancelled. When leadership is
+// lost, for example because the session expired, the instance rejoins the
+// election and may become leader again later.
+func (e *Election) Run(ctx context.Context) error {
+	for {
+		lock := e.client.newLockAt(e.path)
+		if err := lock.Lock(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			e.client.logger.Warn("Failed to campaign for leadership",
+				zap.String("election", e.name),
+				zap.Error(err))
+
+			select {
+			case <-time.After(watchRetryInterval):
+				continue
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		e.lead(ctx, lock)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+}
+
+// IsLeader reports whether this instance currently holds leadership
+func (e *Election) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// lead holds leadership until the lock is lost or ctx is cancelled
+func (e *Election) lead(ctx context.Context, lock *Lock) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	e.leader.Store(true)
+	e.client.logger.Info("Leadership acquired", zap.String("election", e.name))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if e.callbacks.OnStartedLeading != nil {
+			e.callbacks.OnStartedLeading(leaderCtx)
+		}
+	}()
+
+	select {
+	case <-lock.Lost():
+	case <-ctx.Done():
+	}
+
+	e.leader.Store(false)
+	cancel()
+	<-done
+
+	if err := lock.Unlock(); err != nil {
+		e.client.logger.Warn("Failed to release leadership", zap.String("election", e.name), zap.Error(err))
+	}
+	e.client.logger.Info("Leadership lost", zap.String("election", e.name))
+
+	if e.callbacks.OnStoppedLeading != nil {
+		e.callbacks.OnStoppedLeading()
+	}
+}
@@ -0,0 +1,204 @@
+package zookeeper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/magiconair/properties"
+	"gopkg.in/yaml.v3"
+)
+
+// Format identifies how a config document is encoded in its znode
+type Format string
+
+// Supported config formats
+const (
+	FormatAuto       Format = "auto"
+	FormatJSON       Format = "json"
+	FormatBase64JSON Format = "base64-json"
+	FormatYAML       Format = "yaml"
+	FormatProperties Format = "properties"
+)
+
+// Layout identifies how a service's config is spread across znodes
+type Layout string
+
+// Supported config layouts
+const (
+	// LayoutDocument stores the whole config in a single node, the default
+	LayoutDocument Layout = "document"
+
+	// LayoutKeyPerNode stores one znode per key as written by Spring Cloud
+	// Zookeeper; nested child nodes form dotted keys
+	LayoutKeyPerNode Layout = "key-per-node"
+)
+
+// DecodeConfigFormat decodes a config document in the given format. With
+// FormatAuto the format is detected and returned alongside the config.
+func DecodeConfigFormat(data []byte, format Format) (map[string]any, Format, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return map[string]any{}, format, nil
+	}
+
+	if format == "" || format == FormatAuto {
+		format = DetectFormat(data)
+	}
+
+	var config map[string]any
+	var err error
+	switch format {
+	case FormatBase64JSON:
+		config, err = DecodeConfig(data)
+	case FormatJSON:
+		err = json.Unmarshal(data, &config)
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &config)
+	case FormatProperties:
+		config, err = decodeProperties(data)
+	default:
+		return nil, format, fmt.Errorf("unsupported config format %q", format)
+	}
+	if err != nil {
+		return nil, format, fmt.Errorf("failed to parse %s config: %w", format, err)
+	}
+
+	if config == nil {
+		config = map[string]any{}
+	}
+	return config, format, nil
+}
+
+// EncodeConfigFormat encodes a config document in the given format. FormatAuto
+// writes base64 encoded JSON, the historical encoding.
+func EncodeConfigFormat(config map[string]any, format Format) ([]byte, error) {
+	switch format {
+	case "", FormatAuto, FormatBase64JSON:
+		return EncodeConfig(config)
+	case FormatJSON:
+		data, err := json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return data, nil
+	case FormatYAML:
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return data, nil
+	case FormatProperties:
+		return encodeProperties(config)
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+}
+
+// DetectFormat guesses the format of a config document
+func DetectFormat(data []byte) Format {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSON
+	}
+
+	if decoded, err := base64.StdEncoding.DecodeString(string(trimmed)); err == nil {
+		if decoded = bytes.TrimSpace(decoded); len(decoded) > 0 && decoded[0] == '{' {
+			return FormatBase64JSON
+		}
+	}
+
+	var probe map[string]any
+	if err := yaml.Unmarshal(trimmed, &probe); err == nil && looksLikeYAML(trimmed) {
+		return FormatYAML
+	}
+	return FormatProperties
+}
+
+// looksLikeYAML rejects documents whose first entry uses the properties
+// key=value form, which YAML would otherwise accept as a plain scalar
+func looksLikeYAML(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") || line == "---" {
+			continue
+		}
+		colon := strings.Index(line, ":")
+		equals := strings.Index(line, "=")
+		return colon >= 0 && (equals < 0 || colon < equals)
+	}
+	return false
+}
+
+// decodeProperties parses a Java properties file. Dotted keys are expanded
+// into nested objects so that properties and JSON documents look alike.
+func decodeProperties(data []byte) (map[string]any, error) {
+	loader := properties.Loader{Encoding: properties.UTF8, DisableExpansion: true}
+	props, err := loader.LoadBytes(data)
+	if err != nil {
+		return nil, err
+	}
+
+	flat := make(map[string]any, props.Len())
+	for _, key := range props.Keys() {
+		value, _ := props.Get(key)
+		flat[key] = value
+	}
+	return unflattenConfig(flat), nil
+}
+
+// encodeProperties writes a config document as a Java properties file
+func encodeProperties(config map[string]any) ([]byte, error) {
+	flat := flattenConfig(config)
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	props := properties.NewProperties()
+	props.DisableExpansion = true
+	for _, key := range keys {
+		value := flat[key]
+		if _, isString := value.(string); !isString {
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal property %s: %w", key, err)
+			}
+			value = string(encoded)
+		}
+		if _, _, err := props.Set(key, value.(string)); err != nil {
+			return nil, fmt.Errorf("failed to set property %s: %w", key, err)
+		}
+	}
+
+	var buf bytes.Buffer
+	if _, err := props.Write(&buf, properties.UTF8); err != nil {
+		return nil, fmt.Errorf("failed to write properties: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// unflattenConfig expands dotted keys into nested maps. A key that collides
+// with a value already stored at one of its prefixes is kept as a literal
+// dotted key, which lookupPath still resolves.
+func unflattenConfig(flat map[string]any) map[string]any {
+	keys := make([]string, 0, len(flat))
+	for key := range flat {
+		keys = append(keys, key)
+	}
+	// Shorter keys first so that leaf values win over deeper expansions
+	sort.Slice(keys, func(i, j int) bool {
+		return len(keys[i]) < len(keys[j]) || (len(keys[i]) == len(keys[j]) && keys[i] < keys[j])
+	})
+
+	config := make(map[string]any, len(flat))
+	for _, key := range keys {
+		if err := setPath(config, key, flat[key]); err != nil {
+			config[key] = flat[key]
+		}
+	}
+	return config
+}
@@ -0,0 +1,160 @@
+package zookeeper
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetInt retrieves an integer value, accepting numbers and numeric strings
+func (c *Client) GetInt(key string, isCommon bool) (int, error) {
+	value, err := c.GetConfigValueByKey(key, isCommon)
+	if err != nil {
+		return 0, err
+	}
+	result, err := toInt(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid int value for key %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetBool retrieves a boolean value, accepting booleans, numbers such as 0 or 1 and strings such as "true" or "1"
+func (c *Client) GetBool(key string, isCommon bool) (bool, error) {
+	value, err := c.GetConfigValueByKey(key, isCommon)
+	if err != nil {
+		return false, err
+	}
+	result, err := toBool(value)
+	if err != nil {
+		return false, fmt.Errorf("invalid bool value for key %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetDuration retrieves a duration value. Strings are parsed with time.ParseDuration
+// and plain numbers are treated as milliseconds, rounded to the nearest nanosecond.
+func (c *Client) GetDuration(key string, isCommon bool) (time.Duration, error) {
+	value, err := c.GetConfigValueByKey(key, isCommon)
+	if err != nil {
+		return 0, err
+	}
+	result, err := toDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value for key %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetStringSlice retrieves a list of strings from a JSON array or a comma separated string
+func (c *Client) GetStringSlice(key string, isCommon bool) ([]string, error) {
+	value, err := c.GetConfigValueByKey(key, isCommon)
+	if err != nil {
+		return nil, err
+	}
+	result, err := toStringSlice(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid string slice value for key %s: %w", key, err)
+	}
+	return result, nil
+}
+
+// GetOrDefault retrieves a configuration value or returns defaultValue if the key is missing
+func (c *Client) GetOrDefault(key string, isCommon bool, defaultValue any) any {
+	value, exists := c.cache.GetConfig(isCommon, key)
+	if !exists || value == nil {
+		return defaultValue
+	}
+	return value
+}
+
+// toInt coerces a config value into an int
+func toInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v != float64(int(v)) {
+			return 0, fmt.Errorf("%v is not a whole number", v)
+		}
+		return int(v), nil
+	case string:
+		return strconv.Atoi(strings.TrimSpace(v))
+	default:
+		return 0, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// toBool coerces a config value into a bool
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case int:
+		return v != 0, nil
+	case int64:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "yes", "y", "on":
+			return true, nil
+		case "no", "n", "off", "":
+			return false, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(v))
+	default:
+		return false, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// toDuration coerces a config value into a time.Duration
+func toDuration(value any) (time.Duration, error) {
+	switch v := value.(type) {
+	case float64:
+		return time.Duration(math.Round(v * float64(time.Millisecond))), nil
+	case int:
+		return time.Duration(v) * time.Millisecond, nil
+	case int64:
+		return time.Duration(v) * time.Millisecond, nil
+	case string:
+		s := strings.TrimSpace(v)
+		if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+		return time.ParseDuration(s)
+	default:
+		return 0, fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// toStringSlice coerces a config value into a []string
+func toStringSlice(value any) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			result = append(result, fmt.Sprintf("%v", item))
+		}
+		return result, nil
+	case string:
+		if strings.TrimSpace(v) == "" {
+			return []string{}, nil
+		}
+		parts := strings.Split(v, ",")
+		result := make([]string, 0, len(parts))
+		for _, part := range parts {
+			result = append(result, strings.TrimSpace(part))
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %T", value)
+	}
+}
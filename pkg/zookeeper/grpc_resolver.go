@@ -0,0 +1,97 @@
+package zookeeper
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/resolver"
+)
+
+const (
+	// ResolverScheme is the gRPC target scheme resolved through ZooKeeper, as in zk:///auth-service
+	ResolverScheme = "zk"
+
+	// roundRobinServiceConfig enables client-side balancing across resolved instances
+	roundRobinServiceConfig = `{"loadBalancingConfig":[{"round_robin":{}}]}`
+)
+
+// RegisterGRPCResolver registers a gRPC resolver for the zk scheme backed by the given client
+func RegisterGRPCResolver(client *Client) {
+	resolver.Register(&resolverBuilder{client: client})
+}
+
+// resolverBuilder builds resolvers that track service instances in ZooKeeper
+type resolverBuilder struct {
+	client *Client
+}
+
+// Build creates a resolver for the service named in the target path
+func (b *resolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	serviceName := strings.Trim(target.Endpoint(), "/")
+	if serviceName == "" {
+		return nil, fmt.Errorf("missing service name in target %s", target.URL.String())
+	}
+
+	discovery, err := b.client.NewDiscovery(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &grpcResolver{
+		discovery: discovery,
+		cc:        cc,
+		logger:    b.client.logger,
+	}
+	discovery.OnChange(func([]ServiceInstance) { r.update() })
+	r.update()
+
+	return r, nil
+}
+
+// Scheme returns the scheme handled by the builder
+func (b *resolverBuilder) Scheme() string {
+	return ResolverScheme
+}
+
+// grpcResolver pushes discovered gRPC instances into a client connection
+type grpcResolver struct {
+	discovery *Discovery
+	cc        resolver.ClientConn
+	logger    *zap.Logger
+	mu        sync.Mutex
+}
+
+// update pushes the current instances to the client connection. The list is
+// read under the lock so that a racing watch update and the initial push
+// cannot leave an older snapshot in place.
+func (r *grpcResolver) update() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	instances := r.discovery.Instances()
+	addresses := make([]resolver.Address, 0, len(instances))
+	for _, instance := range instances {
+		if instance.Protocol != "" && instance.Protocol != "grpc" {
+			continue
+		}
+		addresses = append(addresses, resolver.Address{Addr: instance.Address()})
+	}
+
+	err := r.cc.UpdateState(resolver.State{
+		Addresses:     addresses,
+		ServiceConfig: r.cc.ParseServiceConfig(roundRobinServiceConfig),
+	})
+	if err != nil {
+		r.logger.Warn("Failed to update gRPC resolver state", zap.Error(err))
+	}
+}
+
+// ResolveNow is a no-op since updates are pushed by ZooKeeper watches
+func (r *grpcResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+// Close stops watching the service
+func (r *grpcResolver) Close() {
+	r.discovery.Close()
+}
@@ -0,0 +1,138 @@
+package zookeeper
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// DefaultHistorySize is the number of config changes kept in memory
+const DefaultHistorySize = 50
+
+// ConfigVersion identifies the config snapshot currently held in the cache.
+// When a profile is active, Profile and ProfileVersion identify the profile
+// node merged onto the base node and Hash covers the merged document.
+type ConfigVersion struct {
+	Version        int32     `json:"version"`
+	Profile        string    `json:"profile,omitempty"`
+	ProfileVersion int32     `json:"profileVersion,omitempty"`
+	Mzxid          int64     `json:"mzxid"`
+	Hash           string    `json:"hash"`
+	LoadedAt       time.Time `json:"loadedAt"`
+}
+
+// String formats the version for logs, for example "v12#3f9a1c0b7d2e" or
+// "v12+prod:v3#3f9a1c0b7d2e"
+func (v ConfigVersion) String() string {
+	if v.Profile != "" {
+		return fmt.Sprintf("v%d+%s:v%d#%s", v.Version, v.Profile, v.ProfileVersion, v.ShortHash())
+	}
+	return fmt.Sprintf("v%d#%s", v.Version, v.ShortHash())
+}
+
+// ShortHash returns an abbreviated snapshot hash suitable for logs
+func (v ConfigVersion) ShortHash() string {
+	if len(v.Hash) < 12 {
+		return v.Hash
+	}
+	return v.Hash[:12]
+}
+
+// ConfigHistoryEntry records a single loaded snapshot and which keys differed
+// from the previous one
+type ConfigHistoryEntry struct {
+	Kind string `json:"kind"`
+	ConfigVersion
+	Changes []KeyChange `json:"changes"`
+}
+
+// KeyChange names a changed config key without its values, which may hold
+// secrets and must not be kept in history or served by VersionHandler
+type KeyChange struct {
+	Key  string     `json:"key"`
+	Type ChangeType `json:"type"`
+}
+
+// keyChanges strips the values from a config diff
+func keyChanges(changes []ConfigChange) []KeyChange {
+	keys := make([]KeyChange, len(changes))
+	for i, change := range changes {
+		keys[i] = KeyChange{Key: change.Key, Type: change.Type}
+	}
+	return keys
+}
+
+// configKind names the config document type for logs and metrics
+func configKind(isCommon bool) string {
+	if isCommon {
+		return "common"
+	}
+	return "service"
+}
+
+// hashConfig returns the SHA-256 of a raw config document
+func hashConfig(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// recordHistory appends an entry, dropping the oldest beyond the history size.
+// The caller must hold the write lock.
+func (cc *ConfigCache) recordHistory(entry ConfigHistoryEntry) {
+	cc.history = append(cc.history, entry)
+	if overflow := len(cc.history) - cc.historySize; overflow > 0 {
+		cc.history = append([]ConfigHistoryEntry(nil), cc.history[overflow:]...)
+	}
+}
+
+// Version returns the version of the cached service or common snapshot
+func (cc *ConfigCache) Version(isCommon bool) ConfigVersion {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	if isCommon {
+		return cc.commonVersion
+	}
+	return cc.serviceVersion
+}
+
+// History returns the recorded config changes, oldest first
+func (cc *ConfigCache) History() []ConfigHistoryEntry {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return append([]ConfigHistoryEntry(nil), cc.history...)
+}
+
+// ConfigVersion returns the version of the cached service or common snapshot
+func (c *Client) ConfigVersion(isCommon bool) ConfigVersion {
+	return c.cache.Version(isCommon)
+}
+
+// ConfigHistory returns the recorded config changes, oldest first
+func (c *Client) ConfigHistory() []ConfigHistoryEntry {
+	return c.cache.History()
+}
+
+// VersionField returns a log field describing the config snapshots in use,
+// for example "service:v12+prod:v3#3f9a1c0b7d2e common:v4#a01b2c3d4e5f"
+func (c *Client) VersionField() zap.Field {
+	return zap.String("config_version", fmt.Sprintf("service:%s common:%s",
+		c.cache.Version(false), c.cache.Version(true)))
+}
+
+// VersionHandler serves the current config versions and change history.
+// Only the names of changed keys are served, never their values.
+func (c *Client) VersionHandler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{
+			"service": c.cache.Version(false),
+			"common":  c.cache.Version(true),
+			"history": c.cache.History(),
+		})
+	}
+}
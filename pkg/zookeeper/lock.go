@@ -0,0 +1,285 @@
+package zookeeper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+const (
+	// LocksBasePath is the root node under which lock nodes are created
+	LocksBasePath = "/locks"
+
+	// lockNodePrefix is the name prefix of the sequential lock nodes
+	lockNodePrefix = "lock-"
+
+	// sequenceLength is the width of the counter ZooKeeper appends to sequential nodes
+	sequenceLength = 10
+)
+
+var (
+	// ErrLockHeld is returned when acquiring a lock that is already held by the same Lock
+	ErrLockHeld = errors.New("lock already held")
+
+	// ErrLockNotHeld is returned when releasing a lock that is not held
+	ErrLockNotHeld = errors.New("lock not held")
+
+	// errSessionLost signals that the lock node vanished with the session and acquisition must restart
+	errSessionLost = errors.New("zookeeper session lost")
+)
+
+// Lock is a distributed mutex built on ephemeral sequential znodes. A Lock
+// must not be shared between goroutines; create one per caller instead.
+type Lock struct {
+	client *Client
+	path   string
+	node   string
+	lost   chan struct{}
+	done   chan struct{}
+	mu     sync.Mutex
+}
+
+// NewLock creates a lock under /locks/<name>
+func (c *Client) NewLock(name string) *Lock {
+	return c.newLockAt(path.Join(LocksBasePath, name))
+}
+
+// newLockAt creates a lock rooted at an arbitrary path
+func (c *Client) newLockAt(lockPath string) *Lock {
+	return &Lock{
+		client: c,
+		path:   c.fullPath(lockPath),
+	}
+}
+
+// Lock blocks until the lock is acquired or the context is cancelled. If the
+// session expires while waiting, acquisition restarts with a fresh node.
+func (l *Lock) Lock(ctx context.Context) error {
+	if l.Held() {
+		return ErrLockHeld
+	}
+
+	if err := l.client.ensurePath(l.path); err != nil {
+		return err
+	}
+
+	for {
+		node, err := l.createNode()
+		if err != nil {
+			return err
+		}
+
+		err = l.waitForTurn(ctx, node)
+		if errors.Is(err, errSessionLost) {
+			l.client.logger.Warn("Lock node lost while waiting, retrying", zap.String("lock", l.path))
+			continue
+		}
+		if err != nil {
+			l.deleteNode(node)
+			return err
+		}
+
+		l.acquired(node)
+		return nil
+	}
+}
+
+// TryLock attempts to acquire the lock without waiting
+func (l *Lock) TryLock() (bool, error) {
+	if l.Held() {
+		return false, ErrLockHeld
+	}
+
+	if err := l.client.ensurePath(l.path); err != nil {
+		return false, err
+	}
+
+	node, err := l.createNode()
+	if err != nil {
+		return false, err
+	}
+
+	predecessor, err := l.predecessor(node)
+	if err != nil || predecessor != "" {
+		l.deleteNode(node)
+		return false, err
+	}
+
+	l.acquired(node)
+	return true, nil
+}
+
+// Unlock releases the lock
+func (l *Lock) Unlock() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.node == "" {
+		return ErrLockNotHeld
+	}
+
+	close(l.done)
+	err := l.client.conn.Delete(l.node, -1)
+	l.node = ""
+	if err != nil && err != zk.ErrNoNode {
+		return fmt.Errorf("failed to release lock %s: %w", l.path, err)
+	}
+	return nil
+}
+
+// Held reports whether this Lock currently believes it holds the lock
+func (l *Lock) Held() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.node != ""
+}
+
+// Lost returns a channel that is closed if the lock node disappears while held,
+// for example after session expiry. It returns nil when the lock is not held.
+func (l *Lock) Lost() <-chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.lost
+}
+
+// createNode creates this contender's ephemeral sequential node
+func (l *Lock) createNode() (string, error) {
+	node, err := l.client.conn.CreateProtectedEphemeralSequential(
+		path.Join(l.path, lockNodePrefix), nil, l.client.acl())
+	if err != nil {
+		return "", fmt.Errorf("failed to create lock node under %s: %w", l.path, err)
+	}
+	return node, nil
+}
+
+// deleteNode removes a node, ignoring errors since the node is ephemeral anyway
+func (l *Lock) deleteNode(node string) {
+	if err := l.client.conn.Delete(node, -1); err != nil && err != zk.ErrNoNode {
+		l.client.logger.Warn("Failed to delete lock node", zap.String("node", node), zap.Error(err))
+	}
+}
+
+// predecessor returns the node queued directly ahead of the given node, or
+// an empty string if the node is first in line
+func (l *Lock) predecessor(node string) (string, error) {
+	children, _, err := l.client.conn.Children(l.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to list lock nodes under %s: %w", l.path, err)
+	}
+
+	name := path.Base(node)
+	sequence := sequenceOf(name)
+	found := false
+	predecessor := ""
+	predecessorSequence := -1
+	for _, child := range children {
+		if child == name {
+			found = true
+			continue
+		}
+		childSequence := sequenceOf(child)
+		if childSequence < sequence && childSequence > predecessorSequence {
+			predecessor = child
+			predecessorSequence = childSequence
+		}
+	}
+
+	if !found {
+		return "", errSessionLost
+	}
+	if predecessor == "" {
+		return "", nil
+	}
+	return path.Join(l.path, predecessor), nil
+}
+
+// waitForTurn blocks until the node is first in line
+func (l *Lock) waitForTurn(ctx context.Context, node string) error {
+	for {
+		predecessor, err := l.predecessor(node)
+		if err != nil || predecessor == "" {
+			return err
+		}
+
+		exists, _, events, err := l.client.conn.ExistsW(predecessor)
+		if err != nil {
+			return fmt.Errorf("failed to watch lock node %s: %w", predecessor, err)
+		}
+		if !exists {
+			continue
+		}
+
+		select {
+		case event := <-events:
+			if event.Err == zk.ErrSessionExpired {
+				return errSessionLost
+			}
+			if event.Type == zk.EventNotWatching {
+				return fmt.Errorf("watch on lock node %s aborted: %w", predecessor, event.Err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// acquired records ownership and starts monitoring the node for loss
+func (l *Lock) acquired(node string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.node = node
+	l.lost = make(chan struct{})
+	l.done = make(chan struct{})
+	go l.monitor(node, l.lost, l.done)
+}
+
+// monitor closes lost when the held node is deleted or its session expires
+func (l *Lock) monitor(node string, lost, done chan struct{}) {
+	for {
+		exists, _, events, err := l.client.conn.ExistsW(node)
+		if err == zk.ErrSessionExpired || err == zk.ErrClosing || err == zk.ErrConnectionClosed || (err == nil && !exists) {
+			l.client.logger.Warn("Lock lost", zap.String("node", node), zap.Error(err))
+			close(lost)
+			return
+		}
+		if err != nil {
+			select {
+			case <-time.After(watchRetryInterval):
+				continue
+			case <-done:
+				return
+			}
+		}
+
+		select {
+		case event := <-events:
+			if event.Type == zk.EventNodeDeleted || event.Type == zk.EventNotWatching {
+				l.client.logger.Warn("Lock lost", zap.String("node", node), zap.Error(event.Err))
+				close(lost)
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// sequenceOf extracts the sequence counter ZooKeeper appended to a node name
+func sequenceOf(name string) int {
+	if len(name) < sequenceLength {
+		return -1
+	}
+	sequence, err := strconv.Atoi(name[len(name)-sequenceLength:])
+	if err != nil {
+		return -1
+	}
+	return sequence
+}
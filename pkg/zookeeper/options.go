@@ -0,0 +1,160 @@
+package zookeeper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ClientOption represents a function that configures the Client
+type ClientOption func(*Config)
+
+// WithHosts sets the ensemble members as host:port pairs
+func WithHosts(hosts ...string) ClientOption {
+	return func(c *Config) {
+		c.Hosts = hosts
+	}
+}
+
+// WithConnectString sets the ensemble from a ZooKeeper connect string such as
+// "zk1:2181,zk2:2181,zk3:2181/market-mosaic". A trailing path sets the chroot.
+func WithConnectString(connectString string) ClientOption {
+	return func(c *Config) {
+		hosts, chroot := ParseConnectString(connectString)
+		c.Hosts = hosts
+		if chroot != "" {
+			c.Chroot = chroot
+		}
+	}
+}
+
+// WithServiceName sets the service whose config is loaded
+func WithServiceName(serviceName string) ClientOption {
+	return func(c *Config) {
+		c.ServiceName = serviceName
+	}
+}
+
+// WithCommonLibName sets the shared config loaded alongside the service config
+func WithCommonLibName(commonLibName string) ClientOption {
+	return func(c *Config) {
+		c.CommonLibName = commonLibName
+	}
+}
+
+// WithRefreshInterval sets how often configuration is reloaded
+func WithRefreshInterval(interval time.Duration) ClientOption {
+	return func(c *Config) {
+		c.RefreshInterval = interval
+	}
+}
+
+// WithConnectionTimeout sets how long to wait for the initial session
+func WithConnectionTimeout(timeout time.Duration) ClientOption {
+	return func(c *Config) {
+		c.ConnectionTimeout = timeout
+	}
+}
+
+// WithSessionTimeout sets the ZooKeeper session timeout
+func WithSessionTimeout(timeout time.Duration) ClientOption {
+	return func(c *Config) {
+		c.SessionTimeout = timeout
+	}
+}
+
+// WithChroot sets the path prefix applied to every node
+func WithChroot(chroot string) ClientOption {
+	return func(c *Config) {
+		c.Chroot = chroot
+	}
+}
+
+// WithDigestAuth authenticates with digest credentials and protects created nodes with a digest ACL
+func WithDigestAuth(user, password string) ClientOption {
+	return func(c *Config) {
+		c.DigestUser = user
+		c.DigestPassword = password
+	}
+}
+
+// WithValidator registers a validator for service or common config
+func WithValidator(isCommon bool, validator Validator) ClientOption {
+	return func(c *Config) {
+		if isCommon {
+			c.CommonValidator = validator
+		} else {
+			c.ServiceValidator = validator
+		}
+	}
+}
+
+// WithFormat sets the encoding of config documents
+func WithFormat(format Format) ClientOption {
+	return func(c *Config) {
+		c.Format = format
+	}
+}
+
+// WithLayout sets how config is spread across znodes
+func WithLayout(layout Layout) ClientOption {
+	return func(c *Config) {
+		c.Layout = layout
+	}
+}
+
+// WithProfile sets the profile whose config is merged onto the base config
+func WithProfile(profile string) ClientOption {
+	return func(c *Config) {
+		c.Profile = profile
+	}
+}
+
+// WithPathTemplates sets the service and common config path templates
+func WithPathTemplates(servicePath, commonPath string) ClientOption {
+	return func(c *Config) {
+		c.ServicePath = servicePath
+		c.CommonPath = commonPath
+	}
+}
+
+// WithoutConfigLoad connects without loading, validating or refreshing the
+// service and common config, so the service name is optional. It suits admin
+// tools that edit config nodes directly and must work even when a node is
+// malformed.
+func WithoutConfigLoad() ClientOption {
+	return func(c *Config) {
+		c.SkipConfigLoad = true
+	}
+}
+
+// ParseConnectString splits a ZooKeeper connect string into hosts and an optional chroot
+func ParseConnectString(connectString string) ([]string, string) {
+	chroot := ""
+	if index := strings.Index(connectString, "/"); index >= 0 {
+		chroot = connectString[index:]
+		connectString = connectString[:index]
+	}
+
+	var hosts []string
+	for _, host := range strings.Split(connectString, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	if chroot == "/" {
+		chroot = ""
+	}
+	return hosts, chroot
+}
+
+// validate checks that a configuration can be used to connect
+func (c *Config) validate() error {
+	if c.ServiceName == "" && !c.SkipConfigLoad {
+		return fmt.Errorf("service name is required")
+	}
+	if len(c.Hosts) == 0 {
+		return fmt.Errorf("at least one ZooKeeper host is required")
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package zookeeper
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sync"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+// Registration represents a service instance registered as an ephemeral znode
+type Registration struct {
+	client   *Client
+	instance ServiceInstance
+	path     string
+	mu       sync.Mutex
+}
+
+// Register publishes a service instance as an ephemeral sequential znode under
+// /services/<name>. The node disappears when the ZooKeeper session ends.
+func (c *Client) Register(instance ServiceInstance) (*Registration, error) {
+	if instance.Name == "" {
+		instance.Name = c.config.ServiceName
+	}
+	if instance.Host == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve hostname: %w", err)
+		}
+		instance.Host = hostname
+	}
+	if instance.Port <= 0 {
+		return nil, fmt.Errorf("invalid port %d for service %s", instance.Port, instance.Name)
+	}
+
+	registration := &Registration{
+		client:   c,
+		instance: instance,
+	}
+	if err := registration.create(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.registrations[registration] = struct{}{}
+	c.mu.Unlock()
+
+	c.logger.Info("Service instance registered",
+		zap.String("service", instance.Name),
+		zap.String("address", instance.Address()),
+		zap.String("path", registration.path))
+	return registration, nil
+}
+
+// create writes the ephemeral node for the registration
+func (r *Registration) create() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	servicePath := r.client.fullPath(path.Join(ServicesBasePath, r.instance.Name))
+	if err := r.client.ensurePath(servicePath); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(r.instance)
+	if err != nil {
+		return fmt.Errorf("failed to marshal service instance: %w", err)
+	}
+
+	nodePath, err := r.client.conn.Create(servicePath+"/instance-", data,
+		zk.FlagEphemeral|zk.FlagSequence, r.client.acl())
+	if err != nil {
+		return fmt.Errorf("failed to register service %s: %w", r.instance.Name, err)
+	}
+	r.path = nodePath
+	return nil
+}
+
+// Path returns the znode path of the registration
+func (r *Registration) Path() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.path
+}
+
+// Instance returns the registered service instance
+func (r *Registration) Instance() ServiceInstance {
+	return r.instance
+}
+
+// Deregister removes the instance znode
+func (r *Registration) Deregister() error {
+	r.client.mu.Lock()
+	delete(r.client.registrations, r)
+	r.client.mu.Unlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.path == "" {
+		return nil
+	}
+	if err := r.client.conn.Delete(r.path, -1); err != nil && err != zk.ErrNoNode {
+		return fmt.Errorf("failed to deregister node %s: %w", r.path, err)
+	}
+
+	r.client.logger.Info("Service instance deregistered", zap.String("path", r.path))
+	r.path = ""
+	return nil
+}
@@ -0,0 +1,126 @@
+package zookeeper
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samuel/go-zookeeper/zk"
+	"go.uber.org/zap"
+)
+
+// zkLogger routes go-zookeeper library logs through zap
+type zkLogger struct {
+	logger *zap.Logger
+}
+
+// Printf implements zk.Logger
+func (l *zkLogger) Printf(format string, args ...any) {
+	l.logger.Debug(fmt.Sprintf(format, args...))
+}
+
+// awaitSession blocks until a session is established or the connection timeout elapses
+func (c *Client) awaitSession(events <-chan zk.Event) error {
+	timeout := time.NewTimer(c.config.ConnectionTimeout)
+	defer timeout.Stop()
+
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return fmt.Errorf("ZooKeeper connection closed before session was established")
+			}
+			if event.Type == zk.EventSession && event.State == zk.StateHasSession {
+				c.logger.Info("ZooKeeper session established",
+					zap.Int64("session_id", c.conn.SessionID()),
+					zap.String("server", event.Server))
+				return nil
+			}
+		case <-timeout.C:
+			return fmt.Errorf("timed out connecting to ZooKeeper after %s", c.config.ConnectionTimeout)
+		}
+	}
+}
+
+// watchSession consumes session events until the connection is closed. The
+// library reconnects on its own, so after an expiry this restores the state
+// that lived in the old session.
+func (c *Client) watchSession(events <-chan zk.Event) {
+	expired := false
+	for event := range events {
+		if event.Type != zk.EventSession {
+			continue
+		}
+
+		switch event.State {
+		case zk.StateDisconnected:
+			c.logger.Warn("ZooKeeper connection lost", zap.String("server", event.Server))
+		case zk.StateExpired:
+			expired = true
+			c.logger.Error("ZooKeeper session expired")
+		case zk.StateHasSession:
+			c.logger.Info("ZooKeeper session established",
+				zap.Int64("session_id", c.conn.SessionID()),
+				zap.String("server", event.Server))
+			if expired {
+				expired = false
+				go c.recoverSession()
+			}
+		}
+	}
+}
+
+// recoverSession re-creates ephemeral registrations and reloads configuration
+// after a new session replaced an expired one
+func (c *Client) recoverSession() {
+	c.mu.Lock()
+	registrations := make([]*Registration, 0, len(c.registrations))
+	for registration := range c.registrations {
+		registrations = append(registrations, registration)
+	}
+	listeners := append([]func(){}, c.listeners...)
+	c.mu.Unlock()
+
+	for _, registration := range registrations {
+		if err := registration.create(); err != nil {
+			c.logger.Error("Failed to restore service registration",
+				zap.String("service", registration.instance.Name),
+				zap.Error(err))
+		}
+	}
+
+	if err := c.loadConfigurations(); err != nil {
+		c.logger.Error("Failed to reload configurations after session expiry", zap.Error(err))
+	}
+
+	for _, listener := range listeners {
+		listener()
+	}
+}
+
+// OnReconnect registers a callback invoked after a new session replaces an expired one
+func (c *Client) OnReconnect(listener func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.listeners = append(c.listeners, listener)
+}
+
+// State returns the current connection state
+func (c *Client) State() zk.State {
+	return c.conn.State()
+}
+
+// IsConnected reports whether the client currently holds a live session
+func (c *Client) IsConnected() bool {
+	return c.conn.State() == zk.StateHasSession
+}
+
+// HealthCheck verifies that the session is live and the ensemble answers requests
+func (c *Client) HealthCheck() error {
+	if state := c.conn.State(); state != zk.StateHasSession {
+		return fmt.Errorf("ZooKeeper session not established: %s", state)
+	}
+	if _, _, err := c.conn.Exists("/"); err != nil {
+		return fmt.Errorf("ZooKeeper health check failed: %w", err)
+	}
+	return nil
+}
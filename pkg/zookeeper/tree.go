@@ -0,0 +1,71 @@
+package zookeeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+
+	"github.com/samuel/go-zookeeper/zk"
+)
+
+// readTree reads a key-per-node config subtree. Leaf nodes become string
+// values and inner nodes become nested objects, so /config/svc/redis/host is
+// available as redis.host.
+func (c *Client) readTree(root string) (map[string]any, ConfigVersion, error) {
+	_, stat, err := c.conn.Get(root)
+	if err != nil {
+		return nil, ConfigVersion{}, err
+	}
+
+	version := ConfigVersion{Version: stat.Version, Mzxid: stat.Mzxid}
+	config, err := c.readChildren(root, &version)
+	if err != nil {
+		return nil, ConfigVersion{}, err
+	}
+
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, ConfigVersion{}, fmt.Errorf("failed to marshal config tree %s: %w", root, err)
+	}
+	version.Hash = hashConfig(data)
+	return config, version, nil
+}
+
+// readChildren recursively reads the children of a node into a map, tracking
+// the most recent modification across the subtree. Nodes deleted while the
+// subtree is read are skipped.
+func (c *Client) readChildren(nodePath string, version *ConfigVersion) (map[string]any, error) {
+	children, _, err := c.conn.Children(nodePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get children of node %s: %w", nodePath, err)
+	}
+
+	config := make(map[string]any, len(children))
+	for _, child := range children {
+		childPath := path.Join(nodePath, child)
+		data, stat, err := c.conn.Get(childPath)
+		if err == zk.ErrNoNode {
+			// Deleted since the children were listed
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get node %s: %w", childPath, err)
+		}
+		if stat.Mzxid > version.Mzxid {
+			version.Mzxid = stat.Mzxid
+		}
+
+		if stat.NumChildren > 0 {
+			nested, err := c.readChildren(childPath, version)
+			if errors.Is(err, zk.ErrNoNode) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			config[child] = nested
+			continue
+		}
+		config[child] = string(data)
+	}
+	return config, nil
+}
@@ -1,6 +1,11 @@
 package zookeeper
 
-import "time"
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
 
 const (
 	// DefaultRefreshInterval is the default time interval for configuration refresh
@@ -11,6 +16,20 @@ const (
 
 	// DefaultSessionTimeout is the default session timeout for ZooKeeper
 	DefaultSessionTimeout = 30 * time.Second
+
+	// DefaultServicePathTemplate locates a service's config node; {service} is
+	// replaced by the service name, suffixed with the profile for overrides
+	DefaultServicePathTemplate = "/config/{service}/config-properties"
+
+	// DefaultCommonPathTemplate locates a shared config node; {common} is
+	// replaced by the common config name, suffixed with the profile for overrides
+	DefaultCommonPathTemplate = "/config/application/{common}"
+
+	// DefaultProfileSeparator joins a name and its profile, as in /config/orders,prod
+	DefaultProfileSeparator = ","
+
+	// meterName is the OpenTelemetry instrumentation scope of this package
+	meterName = "github.com/Kunal726/market-mosaic-common-lib-go/pkg/zookeeper"
 )
 
 // Config represents the ZooKeeper client configuration
@@ -21,6 +40,70 @@ type Config struct {
 	RefreshInterval   time.Duration
 	ConnectionTimeout time.Duration
 	SessionTimeout    time.Duration
+	Chroot            string
+	DigestUser        string
+	DigestPassword    string
+	ServiceValidator  Validator
+	CommonValidator   Validator
+	Format            Format
+	Layout            Layout
+	Profile           string
+	ServicePath       string
+	CommonPath        string
+	ProfileSeparator  string
+	SkipConfigLoad    bool
+}
+
+// ServiceConfigPath returns the node holding a service's configuration
+func ServiceConfigPath(serviceName string) string {
+	return DefaultConfig().serviceConfigPath(serviceName, "")
+}
+
+// CommonConfigPath returns the node holding a shared configuration
+func CommonConfigPath(commonLibName string) string {
+	return DefaultConfig().commonConfigPath(commonLibName, "")
+}
+
+// ServiceConfigPath returns the service config node for the given profile, or
+// the base node when profile is empty
+func (c *Config) ServiceConfigPath(profile string) string {
+	return c.serviceConfigPath(c.ServiceName, profile)
+}
+
+// CommonConfigPath returns the common config node for the given profile, or
+// the base node when profile is empty
+func (c *Config) CommonConfigPath(profile string) string {
+	return c.commonConfigPath(c.CommonLibName, profile)
+}
+
+// serviceConfigPath renders the service path template
+func (c *Config) serviceConfigPath(serviceName, profile string) string {
+	template := c.ServicePath
+	if template == "" {
+		template = DefaultServicePathTemplate
+	}
+	return strings.ReplaceAll(template, "{service}", c.withProfile(serviceName, profile))
+}
+
+// commonConfigPath renders the common path template
+func (c *Config) commonConfigPath(commonLibName, profile string) string {
+	template := c.CommonPath
+	if template == "" {
+		template = DefaultCommonPathTemplate
+	}
+	return strings.ReplaceAll(template, "{common}", c.withProfile(commonLibName, profile))
+}
+
+// withProfile appends the profile to a config name
+func (c *Config) withProfile(name, profile string) string {
+	if profile == "" {
+		return name
+	}
+	separator := c.ProfileSeparator
+	if separator == "" {
+		separator = DefaultProfileSeparator
+	}
+	return name + separator + profile
 }
 
 // DefaultConfig returns a default configuration
@@ -29,5 +112,31 @@ func DefaultConfig() *Config {
 		RefreshInterval:   DefaultRefreshInterval,
 		ConnectionTimeout: DefaultConnectionTimeout,
 		SessionTimeout:    DefaultSessionTimeout,
+		Format:            FormatAuto,
+		Layout:            LayoutDocument,
+		ServicePath:       DefaultServicePathTemplate,
+		CommonPath:        DefaultCommonPathTemplate,
+		ProfileSeparator:  DefaultProfileSeparator,
 	}
 }
+
+const (
+	// ServicesBasePath is the root node under which service instances register
+	ServicesBasePath = "/services"
+)
+
+// ServiceInstance describes a running instance of a service
+type ServiceInstance struct {
+	ID       string            `json:"id,omitempty"`
+	Name     string            `json:"name"`
+	Host     string            `json:"host"`
+	Port     int               `json:"port"`
+	Protocol string            `json:"protocol,omitempty"`
+	Version  string            `json:"version,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// Address returns the host:port address of the instance
+func (s ServiceInstance) Address() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
@@ -0,0 +1,89 @@
+package zookeeper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ErrInvalidConfig is returned when a config document cannot be decoded or
+// fails validation
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Validator checks a decoded config document before it replaces the cached snapshot
+type Validator interface {
+	Validate(config map[string]any) error
+}
+
+// ValidatorFunc adapts a plain function to the Validator interface
+type ValidatorFunc func(config map[string]any) error
+
+// Validate calls f(config)
+func (f ValidatorFunc) Validate(config map[string]any) error {
+	return f(config)
+}
+
+// schemaValidator validates config documents against a JSON Schema
+type schemaValidator struct {
+	schema *jsonschema.Schema
+}
+
+// NewSchemaValidator compiles a JSON Schema document into a Validator
+func NewSchemaValidator(schema string) (Validator, error) {
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("config.json", strings.NewReader(schema)); err != nil {
+		return nil, fmt.Errorf("failed to load config schema: %w", err)
+	}
+
+	compiled, err := compiler.Compile("config.json")
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile config schema: %w", err)
+	}
+	return &schemaValidator{schema: compiled}, nil
+}
+
+// Validate checks the document against the schema
+func (v *schemaValidator) Validate(config map[string]any) error {
+	return v.schema.Validate(config)
+}
+
+// typedValidator decodes config documents into T and validates the struct
+type typedValidator[T any] struct {
+	validate *validator.Validate
+	check    func(*T) error
+}
+
+// NewTypedValidator returns a Validator that decodes the document into T,
+// applies its `validate` struct tags and then runs the optional check.
+func NewTypedValidator[T any](check func(*T) error) Validator {
+	return &typedValidator[T]{
+		validate: validator.New(),
+		check:    check,
+	}
+}
+
+// Validate decodes and validates the document
+func (v *typedValidator[T]) Validate(config map[string]any) error {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	var typed T
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	if err := v.validate.Struct(&typed); err != nil {
+		return err
+	}
+
+	if v.check != nil {
+		return v.check(&typed)
+	}
+	return nil
+}
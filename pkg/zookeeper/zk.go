@@ -1,54 +1,146 @@
 package zookeeper
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/samuel/go-zookeeper/zk"
 	"go.uber.org/zap"
 )
 
+// errEmptyConfig reports a config node without data, which is treated as no
+// change since it is usually a node being created or rewritten
+var errEmptyConfig = errors.New("empty config node")
+
 // Client represents a ZooKeeper client
 type Client struct {
-	conn     *zk.Conn
-	config   *Config
-	cache    *ConfigCache
-	stopChan chan struct{}
-	logger   *zap.Logger
+	conn          *zk.Conn
+	config        *Config
+	cache         *ConfigCache
+	stopChan      chan struct{}
+	stopOnce      sync.Once
+	logger        *zap.Logger
+	registrations map[*Registration]struct{}
+	listeners     []func()
+	mu            sync.Mutex
+}
+
+// NewClient creates a new ZooKeeper client configured from environment
+// variables; options are applied on top of the environment
+func NewClient(logger *zap.Logger, opts ...ClientOption) (*Client, error) {
+	config := ConfigFromEnv()
+	if config.ServiceName == "" && len(opts) == 0 {
+		return nil, fmt.Errorf("SERVICE_NAME environment variable is required")
+	}
+
+	return NewClientFromConfig(config, logger, opts...)
 }
 
-// NewClient creates a new ZooKeeper client
-func NewClient(logger *zap.Logger) (*Client, error) {
+// ConfigFromEnv builds a configuration from environment variables.
+// ZK_CONNECT_STRING takes precedence over ZK_HOST and ZK_PORT.
+func ConfigFromEnv() *Config {
 	config := DefaultConfig()
 
 	// Set configuration from environment variables
-	config.Hosts = []string{fmt.Sprintf("%s:%s",
-		os.Getenv("ZK_HOST"),
-		os.Getenv("ZK_PORT"))}
+	if connectString := os.Getenv("ZK_CONNECT_STRING"); connectString != "" {
+		config.Hosts, config.Chroot = ParseConnectString(connectString)
+	} else {
+		config.Hosts = []string{fmt.Sprintf("%s:%s",
+			os.Getenv("ZK_HOST"),
+			os.Getenv("ZK_PORT"))}
+	}
 	config.ServiceName = os.Getenv("SERVICE_NAME")
 	config.CommonLibName = os.Getenv("COMMON_LIB_NAME")
+	if chroot := os.Getenv("ZK_CHROOT"); chroot != "" {
+		config.Chroot = chroot
+	}
+	config.DigestUser = os.Getenv("ZK_DIGEST_USER")
+	config.DigestPassword = os.Getenv("ZK_DIGEST_PASSWORD")
+	if format := os.Getenv("ZK_CONFIG_FORMAT"); format != "" {
+		config.Format = Format(format)
+	}
+	if layout := os.Getenv("ZK_CONFIG_LAYOUT"); layout != "" {
+		config.Layout = Layout(layout)
+	}
+	if template := os.Getenv("ZK_SERVICE_PATH_TEMPLATE"); template != "" {
+		config.ServicePath = template
+	}
+	if template := os.Getenv("ZK_COMMON_PATH_TEMPLATE"); template != "" {
+		config.CommonPath = template
+	}
+	config.Profile = os.Getenv("ENVIRONMENT")
 
-	if config.ServiceName == "" {
-		return nil, fmt.Errorf("SERVICE_NAME environment variable is required")
+	return config
+}
+
+// NewClientFromConfig creates a new ZooKeeper client from an explicit
+// configuration. A nil config starts from DefaultConfig; options are applied
+// to a copy so the caller's config is never modified.
+func NewClientFromConfig(config *Config, logger *zap.Logger, opts ...ClientOption) (*Client, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	resolved := *config
+	config = &resolved
+
+	// Apply options
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
 	}
 
 	// Connect to ZooKeeper
-	conn, _, err := zk.Connect(config.Hosts, config.SessionTimeout)
+	conn, events, err := zk.Connect(config.Hosts, config.SessionTimeout,
+		zk.WithLogger(&zkLogger{logger: logger}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to ZooKeeper: %w", err)
 	}
 
 	client := &Client{
-		conn:     conn,
-		config:   config,
-		cache:    NewConfigCache(logger),
-		stopChan: make(chan struct{}),
-		logger:   logger,
+		conn:          conn,
+		config:        config,
+		cache:         NewConfigCache(logger),
+		stopChan:      make(chan struct{}),
+		logger:        logger,
+		registrations: make(map[*Registration]struct{}),
+	}
+	client.cache.SetValidator(false, config.ServiceValidator)
+	client.cache.SetValidator(true, config.CommonValidator)
+
+	// Wait for the session before issuing any requests
+	if err := client.awaitSession(events); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if config.DigestUser != "" {
+		credentials := config.DigestUser + ":" + config.DigestPassword
+		if err := conn.AddAuth("digest", []byte(credentials)); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to authenticate with ZooKeeper: %w", err)
+		}
+	}
+
+	// Track session state changes for the lifetime of the client
+	go client.watchSession(events)
+
+	if config.SkipConfigLoad {
+		return client, nil
 	}
 
 	// Initial load of configurations
 	if err := client.loadConfigurations(); err != nil {
+		conn.Close()
 		return nil, fmt.Errorf("failed to load initial configurations: %w", err)
 	}
 
@@ -58,35 +150,123 @@ func NewClient(logger *zap.Logger) (*Client, error) {
 	return client, nil
 }
 
-// loadConfigurations loads both service and common configurations
+// loadConfigurations loads both service and common configurations. A rejected
+// service document does not prevent the common document from refreshing.
 func (c *Client) loadConfigurations() error {
+	var errs []error
+
 	// Load service config
-	servicePath := fmt.Sprintf("/config/%s/config-properties", c.config.ServiceName)
-	data, _, err := c.conn.Get(servicePath)
-	if err != nil && err != zk.ErrNoNode {
-		return fmt.Errorf("failed to get service config: %w", err)
-	}
-	if err == nil {
-		if err := c.cache.UpdateServiceConfig(data); err != nil {
-			return fmt.Errorf("failed to update service config: %w", err)
-		}
+	if err := c.loadConfiguration(false, c.configRoot(false, ""), c.configRoot(false, c.config.Profile)); err != nil {
+		errs = append(errs, fmt.Errorf("failed to update service config: %w", err))
 	}
 
 	// Load common config
 	if c.config.CommonLibName != "" {
-		commonPath := fmt.Sprintf("/config/application/%s", c.config.CommonLibName)
-		data, _, err := c.conn.Get(commonPath)
-		if err != nil && err != zk.ErrNoNode {
-			return fmt.Errorf("failed to get common config: %w", err)
+		if err := c.loadConfiguration(true, c.configRoot(true, ""), c.configRoot(true, c.config.Profile)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to update common config: %w", err))
 		}
-		if err == nil {
-			if err := c.cache.UpdateCommonConfig(data); err != nil {
-				return fmt.Errorf("failed to update common config: %w", err)
-			}
+	}
+
+	return errors.Join(errs...)
+}
+
+// loadConfiguration reads the base config and, when a profile is active, the
+// profile-specific config merged on top of it. Missing nodes are skipped and
+// the cache is left as is when neither exists or either node is empty.
+func (c *Client) loadConfiguration(isCommon bool, basePath, profilePath string) error {
+	base, baseVersion, baseFound, err := c.readConfiguration(isCommon, basePath)
+	if errors.Is(err, errEmptyConfig) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if profilePath == basePath {
+		if !baseFound {
+			return nil
 		}
+		return c.cache.store(isCommon, base, baseVersion)
 	}
 
-	return nil
+	overlay, overlayVersion, overlayFound, err := c.readConfiguration(isCommon, profilePath)
+	if errors.Is(err, errEmptyConfig) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !baseFound && !overlayFound {
+		return nil
+	}
+
+	merged := mergeConfig(base, overlay)
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged config: %w", err)
+	}
+
+	version := ConfigVersion{
+		Version: baseVersion.Version,
+		Mzxid:   max(baseVersion.Mzxid, overlayVersion.Mzxid),
+		Hash:    hashConfig(data),
+	}
+	if overlayFound {
+		version.Profile = c.config.Profile
+		version.ProfileVersion = overlayVersion.Version
+	}
+	return c.cache.store(isCommon, merged, version)
+}
+
+// readConfiguration reads and decodes one config node or subtree according
+// to the configured layout, reporting whether it exists. Documents that cannot
+// be decoded are rejected like documents that fail validation, and an empty
+// document node returns errEmptyConfig.
+func (c *Client) readConfiguration(isCommon bool, nodePath string) (map[string]any, ConfigVersion, bool, error) {
+	if c.config.Layout == LayoutKeyPerNode {
+		config, version, err := c.readTree(nodePath)
+		if errors.Is(err, zk.ErrNoNode) {
+			return nil, ConfigVersion{}, false, nil
+		} else if err != nil {
+			return nil, ConfigVersion{}, false, err
+		}
+		return config, version, true, nil
+	}
+
+	data, stat, err := c.conn.Get(nodePath)
+	if err == zk.ErrNoNode {
+		return nil, ConfigVersion{}, false, nil
+	} else if err != nil {
+		return nil, ConfigVersion{}, false, err
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		c.logger.Warn("Ignoring empty config node, keeping last snapshot",
+			zap.String("kind", configKind(isCommon)),
+			zap.String("path", nodePath))
+		return nil, ConfigVersion{}, false, errEmptyConfig
+	}
+
+	config, _, err := DecodeConfigFormat(data, c.config.Format)
+	if err != nil {
+		return nil, ConfigVersion{}, false, c.cache.reject(isCommon, rejectDecode, fmt.Errorf("node %s: %w", nodePath, err))
+	}
+	return config, ConfigVersion{
+		Version: stat.Version,
+		Mzxid:   stat.Mzxid,
+		Hash:    hashConfig(data),
+	}, true, nil
+}
+
+// configRoot returns the node holding service or common config for the given
+// profile. In the key-per-node layout the service config lives in the parent
+// of the document node, as in /config/<service>.
+func (c *Client) configRoot(isCommon bool, profile string) string {
+	if isCommon {
+		return c.fullPath(c.config.CommonConfigPath(profile))
+	}
+	if c.config.Layout == LayoutKeyPerNode {
+		return c.fullPath(path.Dir(c.config.ServiceConfigPath(profile)))
+	}
+	return c.fullPath(c.config.ServiceConfigPath(profile))
 }
 
 // startConfigRefresh starts a background goroutine to refresh configurations
@@ -98,7 +278,7 @@ func (c *Client) startConfigRefresh() {
 		select {
 		case <-ticker.C:
 			if err := c.loadConfigurations(); err != nil {
-				c.logger.Error("Failed to refresh configurations", zap.Error(err))
+				c.logger.Error("Failed to refresh configurations", zap.Error(err), c.VersionField())
 			}
 		case <-c.stopChan:
 			return
@@ -128,6 +308,12 @@ func (c *Client) GetConfigValueByKey(key string, isCommon bool) (any, error) {
 	return value, nil
 }
 
+// SetValidator registers a validator for service or common config and checks
+// every subsequent refresh against it
+func (c *Client) SetValidator(isCommon bool, validator Validator) {
+	c.cache.SetValidator(isCommon, validator)
+}
+
 // RefreshData manually triggers a refresh of the configurations
 func (c *Client) RefreshData() {
 	c.logger.Info("Manual ZooKeeper config refresh triggered")
@@ -138,9 +324,9 @@ func (c *Client) RefreshData() {
 
 // Get retrieves the data and stat of a node
 func (c *Client) Get(path string) ([]byte, *zk.Stat, error) {
-	data, stat, err := c.conn.Get(path)
+	data, stat, err := c.conn.Get(c.fullPath(path))
 	if err == zk.ErrNoNode {
-		return nil, nil, fmt.Errorf("node %s does not exist", path)
+		return nil, nil, fmt.Errorf("node %s: %w", path, zk.ErrNoNode)
 	} else if err != nil {
 		return nil, nil, fmt.Errorf("failed to get node %s: %w", path, err)
 	}
@@ -149,9 +335,9 @@ func (c *Client) Get(path string) ([]byte, *zk.Stat, error) {
 
 // GetChildren retrieves the children of a node
 func (c *Client) GetChildren(path string) ([]string, error) {
-	children, _, err := c.conn.Children(path)
+	children, _, err := c.conn.Children(c.fullPath(path))
 	if err == zk.ErrNoNode {
-		return nil, fmt.Errorf("node %s does not exist", path)
+		return nil, fmt.Errorf("node %s: %w", path, zk.ErrNoNode)
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to get children of node %s: %w", path, err)
 	}
@@ -160,15 +346,50 @@ func (c *Client) GetChildren(path string) ([]string, error) {
 
 // Exists checks if a node exists
 func (c *Client) Exists(path string) (bool, error) {
-	exists, _, err := c.conn.Exists(path)
+	exists, _, err := c.conn.Exists(c.fullPath(path))
 	if err != nil {
 		return false, fmt.Errorf("failed to check existence of node %s: %w", path, err)
 	}
 	return exists, nil
 }
 
-// Close closes the ZooKeeper connection and stops the refresh goroutine
+// fullPath prefixes a path with the configured chroot
+func (c *Client) fullPath(p string) string {
+	if c.config.Chroot == "" {
+		return p
+	}
+	return path.Join("/", c.config.Chroot, p)
+}
+
+// acl returns the ACL applied to nodes created by this client
+func (c *Client) acl() []zk.ACL {
+	if c.config.DigestUser != "" {
+		return zk.DigestACL(zk.PermAll, c.config.DigestUser, c.config.DigestPassword)
+	}
+	return zk.WorldACL(zk.PermAll)
+}
+
+// ensurePath creates any missing persistent nodes along the given path
+func (c *Client) ensurePath(path string) error {
+	current := ""
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part == "" {
+			continue
+		}
+		current += "/" + part
+		_, err := c.conn.Create(current, nil, 0, c.acl())
+		if err != nil && err != zk.ErrNodeExists {
+			return fmt.Errorf("failed to create node %s: %w", current, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the ZooKeeper connection and stops the refresh goroutine. It
+// is safe to call more than once.
 func (c *Client) Close() {
-	close(c.stopChan)
-	c.conn.Close()
+	c.stopOnce.Do(func() {
+		close(c.stopChan)
+		c.conn.Close()
+	})
 }
@@ -1,34 +1,94 @@
 package repositories
 
 import (
+	"context"
+
+	"github.com/Kunal726/market-mosaic-common-lib-go/pkg/db"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
-// BaseRepository interface defines common database operations
+// BaseRepository interface defines common database operations. They always
+// use the repository's database; use the ContextRepository methods to take
+// part in the transaction started by db.TxManager.WithTransaction.
 type BaseRepository[T any, ID any] interface {
+	// Deprecated: Use ContextRepository.FindAllContext, which joins the ambient transaction.
 	FindAll(logger *zap.Logger) ([]T, error)
+	// Deprecated: Use ContextRepository.FindByIDContext, which joins the ambient transaction.
 	FindByID(logger *zap.Logger, id ID) (*T, error)
+	// Deprecated: Use ContextRepository.SaveContext, which joins the ambient transaction.
 	Save(logger *zap.Logger, entity *T) error
+	// Deprecated: Use ContextRepository.DeleteContext, which joins the ambient transaction.
 	Delete(logger *zap.Logger, id ID) error
 }
 
-// BaseRepositoryImpl implements BaseRepository
+// ContextRepository extends BaseRepository with operations that run in the
+// ambient transaction of ctx, if any
+type ContextRepository[T any, ID any] interface {
+	BaseRepository[T, ID]
+	FindAllContext(ctx context.Context, logger *zap.Logger) ([]T, error)
+	FindByIDContext(ctx context.Context, logger *zap.Logger, id ID) (*T, error)
+	SaveContext(ctx context.Context, logger *zap.Logger, entity *T) error
+	DeleteContext(ctx context.Context, logger *zap.Logger, id ID) error
+}
+
+// BaseRepositoryImpl implements BaseRepository and ContextRepository
 type BaseRepositoryImpl[T any, ID any] struct {
 	db *gorm.DB
 }
 
 func NewBaseRepository[T any, ID any](db *gorm.DB) BaseRepository[T, ID] {
+	return NewContextRepository[T, ID](db)
+}
+
+// NewContextRepository creates a repository whose Context methods take part
+// in the ambient transaction
+func NewContextRepository[T any, ID any](db *gorm.DB) ContextRepository[T, ID] {
 	return &BaseRepositoryImpl[T, ID]{
 		db: db,
 	}
 }
 
+// FindAll returns every entity outside of any transaction.
+//
+// Deprecated: Use FindAllContext, which joins the ambient transaction.
 func (r *BaseRepositoryImpl[T, ID]) FindAll(logger *zap.Logger) ([]T, error) {
+	return r.FindAllContext(context.Background(), logger)
+}
+
+// FindByID returns the entity with the given id outside of any transaction.
+//
+// Deprecated: Use FindByIDContext, which joins the ambient transaction.
+func (r *BaseRepositoryImpl[T, ID]) FindByID(logger *zap.Logger, id ID) (*T, error) {
+	return r.FindByIDContext(context.Background(), logger, id)
+}
+
+// Save creates or updates an entity outside of any transaction. Inside
+// WithTransaction it commits on its own and may block on a pool whose only
+// connection is held by the transaction.
+//
+// Deprecated: Use SaveContext, which joins the ambient transaction.
+func (r *BaseRepositoryImpl[T, ID]) Save(logger *zap.Logger, entity *T) error {
+	return r.SaveContext(context.Background(), logger, entity)
+}
+
+// Delete removes the entity with the given id outside of any transaction.
+//
+// Deprecated: Use DeleteContext, which joins the ambient transaction.
+func (r *BaseRepositoryImpl[T, ID]) Delete(logger *zap.Logger, id ID) error {
+	return r.DeleteContext(context.Background(), logger, id)
+}
+
+// conn returns the ambient transaction of ctx or the repository's database
+func (r *BaseRepositoryImpl[T, ID]) conn(ctx context.Context) *gorm.DB {
+	return db.Conn(ctx, r.db)
+}
+
+func (r *BaseRepositoryImpl[T, ID]) FindAllContext(ctx context.Context, logger *zap.Logger) ([]T, error) {
 	logger.Info("Finding all entities")
 
 	var entities []T
-	err := r.db.Find(&entities).Error
+	err := r.conn(ctx).Find(&entities).Error
 	if err != nil {
 		logger.Error("Failed to find all entities", zap.Error(err))
 		return nil, err
@@ -39,11 +99,11 @@ func (r *BaseRepositoryImpl[T, ID]) FindAll(logger *zap.Logger) ([]T, error) {
 	return entities, nil
 }
 
-func (r *BaseRepositoryImpl[T, ID]) FindByID(logger *zap.Logger, id ID) (*T, error) {
+func (r *BaseRepositoryImpl[T, ID]) FindByIDContext(ctx context.Context, logger *zap.Logger, id ID) (*T, error) {
 	logger.Info("Finding entity by ID", zap.Any("id", id))
 
 	var entity T
-	err := r.db.First(&entity, id).Error
+	err := r.conn(ctx).First(&entity, id).Error
 	if err != nil {
 		logger.Error("Failed to find entity by ID",
 			zap.Error(err),
@@ -55,10 +115,10 @@ func (r *BaseRepositoryImpl[T, ID]) FindByID(logger *zap.Logger, id ID) (*T, err
 	return &entity, nil
 }
 
-func (r *BaseRepositoryImpl[T, ID]) Save(logger *zap.Logger, entity *T) error {
+func (r *BaseRepositoryImpl[T, ID]) SaveContext(ctx context.Context, logger *zap.Logger, entity *T) error {
 	logger.Info("Saving entity")
 
-	if err := r.db.Save(entity).Error; err != nil {
+	if err := r.conn(ctx).Save(entity).Error; err != nil {
 		logger.Error("Failed to save entity", zap.Error(err))
 		return err
 	}
@@ -67,11 +127,11 @@ func (r *BaseRepositoryImpl[T, ID]) Save(logger *zap.Logger, entity *T) error {
 	return nil
 }
 
-func (r *BaseRepositoryImpl[T, ID]) Delete(logger *zap.Logger, id ID) error {
+func (r *BaseRepositoryImpl[T, ID]) DeleteContext(ctx context.Context, logger *zap.Logger, id ID) error {
 	logger.Info("Deleting entity", zap.Any("id", id))
 
 	var entity T
-	if err := r.db.Delete(&entity, id).Error; err != nil {
+	if err := r.conn(ctx).Delete(&entity, id).Error; err != nil {
 		logger.Error("Failed to delete entity",
 			zap.Error(err),
 			zap.Any("id", id))